@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"vastestsea/internal/auth"
+	"vastestsea/internal/store"
+)
+
+const (
+	testAPIKey         = "test-key"
+	testEditorPassword = "correct horse"
+)
+
+// Serves the whole API and the web editor over the in-memory store.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &apiConfig{
+		store: store.NewMemoryStore(),
+		auth: auth.AuthConfig{
+			ApiKey:         testAPIKey,
+			EditorPassword: testEditorPassword,
+			Sessions:       auth.NewSessionStore(time.Hour, false),
+		},
+		hostName:    "vastestsea.test",
+		tokenCounts: newTokenCounts(),
+	}
+	server := httptest.NewServer(cfg.newServeMux())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Sends an authenticated request with body encoded as JSON, and decodes the
+// response into out, if given. Returns the response status.
+func call(t *testing.T, server *httptest.Server, method, path string, body any, out any) int {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "ApiKey "+testAPIKey)
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if out != nil && res.StatusCode < 300 {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decoding response: %s", method, path, err)
+		}
+	}
+	return res.StatusCode
+}
+
+func mustCall(t *testing.T, server *httptest.Server, method, path string, body any, out any, want int) {
+	t.Helper()
+	if status := call(t, server, method, path, body, out); status != want {
+		t.Fatalf("%s %s: got status %d, want %d", method, path, status, want)
+	}
+}
+
+func TestLanguageNamesAreUnique(t *testing.T) {
+	server := newTestServer(t)
+
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusUnprocessableEntity)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "sindarin"}, nil, http.StatusCreated)
+
+	// Renaming onto a taken name is refused too.
+	mustCall(t, server, "PUT", "/vs/languages/sindarin", map[string]string{"name": "quenya"}, nil, http.StatusUnprocessableEntity)
+}
+
+func TestWordsAreUniquePerLanguage(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "sindarin"}, nil, http.StatusCreated)
+
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusUnprocessableEntity)
+	// The same word may belong to another language.
+	mustCall(t, server, "POST", "/vs/languages/sindarin/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+}
+
+func TestDefinitionsAreUniquePerWord(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "meldo"}, nil, http.StatusCreated)
+
+	friend := map[string]string{"content": "friend", "part_of_speech": "noun"}
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/mellon/definitions", friend, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/mellon/definitions", friend, nil, http.StatusUnprocessableEntity)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/meldo/definitions", friend, nil, http.StatusCreated)
+
+	// A batch containing a duplicate is rolled back as a whole.
+	batch := []map[string]string{
+		{"content": "ally", "part_of_speech": "noun"},
+		friend,
+	}
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/mellon/definitions", batch, nil, http.StatusUnprocessableEntity)
+	definitions := []Definition{}
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon/definitions", nil, &definitions, http.StatusOK)
+	if len(definitions) != 1 {
+		t.Fatalf("got %d definitions after a failed batch, want 1", len(definitions))
+	}
+}
+
+func TestDeletingAWordDeletesItsDefinitions(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+
+	created := []Definition{}
+	friend := map[string]string{"content": "friend", "part_of_speech": "noun"}
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/mellon/definitions", friend, &created, http.StatusCreated)
+
+	mustCall(t, server, "DELETE", "/vs/languages/quenya/words/mellon", nil, nil, http.StatusOK)
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon", nil, nil, http.StatusNotFound)
+
+	// Recreating the word starts it without definitions.
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+	definitions := []Definition{}
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon/definitions", nil, &definitions, http.StatusOK)
+	if len(definitions) != 0 {
+		t.Fatalf("got %d definitions on a recreated word, want 0", len(definitions))
+	}
+}
+
+func TestDeletingALanguageDeletesItsWords(t *testing.T) {
+	server := newTestServer(t)
+	language := Language{}
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, &language, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+	friend := map[string]string{"content": "friend", "part_of_speech": "noun"}
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/mellon/definitions", friend, nil, http.StatusCreated)
+
+	mustCall(t, server, "DELETE", "/vs/languages", map[string]any{"id": language.ID}, nil, http.StatusNoContent)
+	mustCall(t, server, "GET", "/vs/languages/quenya", nil, nil, http.StatusNotFound)
+
+	// A new language of the same name shares nothing with the old one.
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon", nil, nil, http.StatusNotFound)
+
+	page := Page[Word]{}
+	mustCall(t, server, "GET", "/vs/languages/words", nil, &page, http.StatusOK)
+	if len(page.Data) != 0 {
+		t.Fatalf("got %d words after deleting their language, want 0", len(page.Data))
+	}
+}
+
+func TestFailedUpdateWordRollsBack(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "meldo"}, nil, http.StatusCreated)
+
+	// Creating mellon succeeds, but renaming it onto meldo does not, so
+	// mellon must not be left behind.
+	update := map[string]any{
+		"word": "meldo",
+		"definition": map[string]any{
+			"add": map[string]string{"content": "friend", "part_of_speech": "noun"},
+		},
+	}
+	mustCall(t, server, "PUT", "/vs/languages/quenya/words/mellon", update, nil, http.StatusUnprocessableEntity)
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon", nil, nil, http.StatusNotFound)
+}
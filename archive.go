@@ -0,0 +1,468 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"vastestsea/internal/database"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Archive Handlers
+ */
+
+// Identifies archives written by the export endpoint. The version is bumped
+// whenever the layout changes in a way older readers would misread.
+const (
+	archiveFormat  = "vastestsea"
+	archiveVersion = 1
+)
+
+// How an import treats rows whose IDs already exist.
+const (
+	restoreReplace = "replace"
+	restoreMerge   = "merge"
+	restoreFail    = "fail"
+)
+
+// Writes the members of a JSON object one at a time, so that an archive can
+// be streamed without holding every row in memory.
+type archiveWriter struct {
+	w     io.Writer
+	err   error
+	first bool
+}
+
+func (a *archiveWriter) raw(s string) {
+	if a.err == nil {
+		_, a.err = io.WriteString(a.w, s)
+	}
+}
+
+func (a *archiveWriter) value(v any) {
+	if a.err != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		a.err = err
+		return
+	}
+	_, a.err = a.w.Write(data)
+}
+
+// Starts a member holding an array, whose elements are written with element.
+func (a *archiveWriter) beginArray(key string) {
+	a.raw(fmt.Sprintf(",\n%q: [", key))
+	a.first = true
+}
+
+func (a *archiveWriter) element(v any) {
+	if a.first {
+		a.raw("\n")
+	} else {
+		a.raw(",\n")
+	}
+	a.first = false
+	a.value(v)
+}
+
+func (a *archiveWriter) endArray() {
+	a.raw("\n]")
+}
+
+// Stream an archive of every language, word and definition, keeping their
+// IDs and timestamps so that they can be restored exactly. Every row is read
+// from one snapshot, so the archive is consistent however long it takes to
+// stream. The archive ends with a "complete" member, which is only written
+// once everything else has been; an archive cut short by an error is refused
+// by imports.
+func (cfg *apiConfig) exportArchive(w http.ResponseWriter, r *http.Request) {
+	// Nil until the response has begun.
+	var archive *archiveWriter
+	err := cfg.store.RunInSnapshot(r.Context(), func(tx store.Store) error {
+		languages, err := tx.GetLanguages(r.Context())
+		if err != nil {
+			return err
+		}
+
+		exportedAt := time.Now().UTC()
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(
+			"attachment; filename=%q",
+			"vastestsea-"+exportedAt.Format("20060102-150405")+".json",
+		))
+
+		flush := func() {
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+
+		archive = &archiveWriter{w: w}
+		archive.raw(fmt.Sprintf(`{"format": %q, "version": %d, "exported_at": `, archiveFormat, archiveVersion))
+		archive.value(exportedAt)
+
+		archive.beginArray("languages")
+		for _, language := range languages {
+			archive.element(getMarshallableLanguage(language))
+		}
+		archive.endArray()
+
+		// Words and definitions are fetched a language at a time.
+		wordIDsByLanguage := map[uuid.UUID][]uuid.UUID{}
+		archive.beginArray("words")
+		for _, language := range languages {
+			words, err := tx.GetWordsByLanguageID(r.Context(), language.ID)
+			if err != nil {
+				return fmt.Errorf("words of %s: %w", language.Name, err)
+			}
+			for _, word := range words {
+				wordIDsByLanguage[language.ID] = append(wordIDsByLanguage[language.ID], word.ID)
+				archive.element(getArchivedWord(word))
+			}
+			flush()
+		}
+		archive.endArray()
+
+		archive.beginArray("definitions")
+		for _, language := range languages {
+			definitions, err := tx.GetDefinitionsOfWords(r.Context(), wordIDsByLanguage[language.ID])
+			if err != nil {
+				return fmt.Errorf("definitions of %s: %w", language.Name, err)
+			}
+			for _, definition := range definitions {
+				archive.element(getMarshallableDefinition(definition))
+			}
+			flush()
+		}
+		archive.endArray()
+
+		if archive.err != nil {
+			return archive.err
+		}
+		archive.raw(",\n\"complete\": true}\n")
+		return archive.err
+	})
+	if err != nil {
+		if archive == nil {
+			respondError("Failed to retrieve languages", w, http.StatusInternalServerError)
+			return
+		}
+		// The status has been sent, so all that can be done is to leave the
+		// archive without its "complete" member.
+		log.Printf("Error exporting archive: %s", err)
+	}
+}
+
+// Checks that an archive can be restored, before anything is written.
+func (a Archive) validate() error {
+	if a.Format != archiveFormat {
+		return fmt.Errorf("format must be %q", archiveFormat)
+	}
+	if a.Version < 1 || a.Version > archiveVersion {
+		return fmt.Errorf("version %d is not supported, the latest is %d", a.Version, archiveVersion)
+	}
+	if !a.Complete {
+		return errors.New("the archive is incomplete, as if its export was cut short")
+	}
+	for i, l := range a.Languages {
+		if l.ID == uuid.Nil || l.Name == "" {
+			return fmt.Errorf("language %d needs an id and a name", i)
+		}
+	}
+	for i, w := range a.Words {
+		if w.ID == uuid.Nil || w.Word == "" || w.LanguageID == uuid.Nil {
+			return fmt.Errorf("word %d needs an id, a word and a language_id", i)
+		}
+	}
+	for i, d := range a.Definitions {
+		if d.ID == uuid.Nil || d.Content == "" || d.WordID == uuid.Nil {
+			return fmt.Errorf("definition %d needs an id, content and a word_id", i)
+		}
+	}
+	return nil
+}
+
+// Fails with a 409 if a row with the given ID exists, for the fail strategy.
+func conflictIfExists(kind string, id uuid.UUID, lookup func() error) error {
+	if err := lookup(); err == nil {
+		return stepError(
+			fmt.Sprintf("restore %s %s", kind, id),
+			fmt.Errorf("a %s with this id already exists", kind),
+			http.StatusConflict,
+		)
+	}
+	return nil
+}
+
+func countRestored(counts *RestoreCounts, rows int64) {
+	if rows > 0 {
+		counts.Restored++
+	} else {
+		counts.Unchanged++
+	}
+}
+
+// The tables that replacing every language would empty, though archives do
+// not hold them.
+func unarchivedTables(counts database.CountUnarchivedRowsRow) []string {
+	tables := []string{}
+	for _, t := range []struct {
+		name string
+		rows int64
+	}{
+		{"word relations", counts.WordRelations},
+		{"translations", counts.Translations},
+		{"phonologies", counts.Phonologies},
+		{"inflection classes", counts.InflectionClasses},
+		{"lemma rules", counts.LemmaRules},
+		{"examples", counts.Examples},
+		{"texts", counts.Texts},
+	} {
+		if t.rows > 0 {
+			tables = append(tables, t.name)
+		}
+	}
+	return tables
+}
+
+// Restore an archive made by the export endpoint, in a single transaction.
+// The `strategy` query parameter decides what happens to rows whose IDs
+// already exist:
+//   - `fail`, the default, rolls back the import.
+//   - `merge` keeps whichever copy was updated most recently.
+//   - `replace` deletes every language first, so that the archive replaces
+//     the dictionary entirely. As archives only hold languages, words and
+//     definitions, it is refused while anything else is stored, since
+//     deleting the languages would delete that too.
+//
+// A row whose name is already held by a row with another ID, such as a word
+// spelled like another of its language, fails the import with a 409, except
+// under `merge`, which skips it and everything that belongs to it, and lists
+// what it skipped.
+func (cfg *apiConfig) importArchive(w http.ResponseWriter, r *http.Request) {
+	strategy := r.URL.Query().Get("strategy")
+	if strategy == "" {
+		strategy = restoreFail
+	}
+	if strategy != restoreReplace && strategy != restoreMerge && strategy != restoreFail {
+		respondError("Invalid strategy: must be replace, merge or fail", w, http.StatusBadRequest)
+		return
+	}
+
+	archive := Archive{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&archive); err != nil {
+		respondError(fmt.Sprintf("Could not decode archive: %s", err), w, http.StatusBadRequest)
+		return
+	}
+	if err := archive.validate(); err != nil {
+		respondError(fmt.Sprintf("Invalid archive: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	result := ArchiveImport{
+		Strategy: strategy,
+		Version:  archive.Version,
+		Skipped:  []SkippedRow{},
+	}
+	err := cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		if strategy == restoreReplace {
+			counts, err := tx.CountUnarchivedRows(r.Context())
+			if err != nil {
+				return stepError("count unarchived rows", err, http.StatusInternalServerError)
+			}
+			if tables := unarchivedTables(counts); len(tables) > 0 {
+				return stepError(
+					"delete existing languages",
+					fmt.Errorf("archives do not hold the stored %s, which replacing would delete", strings.Join(tables, ", ")),
+					http.StatusConflict,
+				)
+			}
+			if err := tx.DeleteAllLanguages(r.Context()); err != nil {
+				return stepError("delete existing languages", err, http.StatusInternalServerError)
+			}
+		}
+
+		// Skips a row under merge, and fails the import otherwise.
+		skip := func(counts *RestoreCounts, row SkippedRow) error {
+			if strategy != restoreMerge {
+				return stepError(
+					fmt.Sprintf("restore %s %q", row.Kind, row.Name),
+					errors.New(row.Reason),
+					http.StatusConflict,
+				)
+			}
+			counts.Skipped++
+			result.Skipped = append(result.Skipped, row)
+			return nil
+		}
+		// Skipped rows that were not already stored, whose own rows must be
+		// skipped too.
+		missingLanguages := map[uuid.UUID]bool{}
+		missingWords := map[uuid.UUID]bool{}
+
+		for _, l := range archive.Languages {
+			if strategy == restoreFail {
+				err := conflictIfExists("language", l.ID, func() error {
+					_, err := tx.GetLanguageByID(r.Context(), l.ID)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			holder, err := tx.GetLanguage(r.Context(), strings.ToLower(l.Name))
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return stepError(fmt.Sprintf("restore language %q", l.Name), err, http.StatusInternalServerError)
+			}
+			if err == nil && holder.ID != l.ID {
+				err := skip(&result.Languages, SkippedRow{
+					Kind:   "language",
+					ID:     l.ID,
+					Name:   l.Name,
+					Reason: fmt.Sprintf("language %s already has this name", holder.ID),
+				})
+				if err != nil {
+					return err
+				}
+				if _, err := tx.GetLanguageByID(r.Context(), l.ID); err != nil {
+					missingLanguages[l.ID] = true
+				}
+				continue
+			}
+
+			rows, err := tx.RestoreLanguage(r.Context(), database.RestoreLanguageParams{
+				ID:        l.ID,
+				CreatedAt: l.CreatedAt,
+				UpdatedAt: l.UpdatedAt,
+				Name:      l.Name,
+			})
+			if err != nil {
+				return stepError(fmt.Sprintf("restore language %q", l.Name), err, getFailedCreationCode(err))
+			}
+			countRestored(&result.Languages, rows)
+		}
+
+		for _, word := range archive.Words {
+			if strategy == restoreFail {
+				err := conflictIfExists("word", word.ID, func() error {
+					_, err := tx.GetWordByID(r.Context(), word.ID)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			reason := ""
+			if missingLanguages[word.LanguageID] {
+				reason = "its language was skipped"
+			} else {
+				holder, err := tx.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+					Word:       strings.ToLower(word.Word),
+					LanguageID: word.LanguageID,
+				})
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					return stepError(fmt.Sprintf("restore word %q", word.Word), err, http.StatusInternalServerError)
+				}
+				if err == nil && holder.ID != word.ID {
+					reason = fmt.Sprintf("word %s of the same language is already spelled this way", holder.ID)
+				}
+			}
+			if reason != "" {
+				err := skip(&result.Words, SkippedRow{Kind: "word", ID: word.ID, Name: word.Word, Reason: reason})
+				if err != nil {
+					return err
+				}
+				if _, err := tx.GetWordByID(r.Context(), word.ID); err != nil {
+					missingWords[word.ID] = true
+				}
+				continue
+			}
+
+			params := database.RestoreWordParams{
+				ID:         word.ID,
+				CreatedAt:  word.CreatedAt,
+				UpdatedAt:  word.UpdatedAt,
+				Word:       word.Word,
+				LanguageID: word.LanguageID,
+			}
+			if word.FontFormatted != nil {
+				params.FontFormatted.String = *word.FontFormatted
+				params.FontFormatted.Valid = true
+			}
+			rows, err := tx.RestoreWord(r.Context(), params)
+			if err != nil {
+				return stepError(fmt.Sprintf("restore word %q", word.Word), err, getFailedCreationCode(err))
+			}
+			countRestored(&result.Words, rows)
+		}
+
+		for _, d := range archive.Definitions {
+			if strategy == restoreFail {
+				err := conflictIfExists("definition", d.ID, func() error {
+					_, err := tx.GetDefinitionByID(r.Context(), d.ID)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			reason := ""
+			if missingWords[d.WordID] {
+				reason = "its word was skipped"
+			} else {
+				siblings, err := tx.GetDefinitionsOfWord(r.Context(), d.WordID)
+				if err != nil {
+					return stepError(fmt.Sprintf("restore definition %s", d.ID), err, http.StatusInternalServerError)
+				}
+				for _, sibling := range siblings {
+					if sibling.Content == d.Content && sibling.ID != d.ID {
+						reason = fmt.Sprintf("definition %s of the same word already reads this way", sibling.ID)
+					}
+				}
+			}
+			if reason != "" {
+				err := skip(&result.Definitions, SkippedRow{Kind: "definition", ID: d.ID, Name: d.Content, Reason: reason})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			rows, err := tx.RestoreDefinition(r.Context(), database.RestoreDefinitionParams{
+				ID:           d.ID,
+				CreatedAt:    d.CreatedAt,
+				UpdatedAt:    d.UpdatedAt,
+				Content:      d.Content,
+				PartOfSpeech: d.PartOfSpeech,
+				WordID:       d.WordID,
+			})
+			if err != nil {
+				return stepError(fmt.Sprintf("restore definition %s", d.ID), err, getFailedCreationCode(err))
+			}
+			countRestored(&result.Definitions, rows)
+		}
+
+		return nil
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	writeResponse(result, w, http.StatusOK)
+}
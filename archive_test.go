@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Creates quenya, with mellon defined as friend, and exports it.
+func exportTestArchive(t *testing.T) Archive {
+	t.Helper()
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+	friend := map[string]string{"content": "friend", "part_of_speech": "noun"}
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/mellon/definitions", friend, nil, http.StatusCreated)
+
+	archive := Archive{}
+	mustCall(t, server, "GET", "/vs/admin/export", nil, &archive, http.StatusOK)
+	if !archive.Complete {
+		t.Fatal("exported archive is not marked complete")
+	}
+	return archive
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	archive := exportTestArchive(t)
+	server := newTestServer(t)
+
+	result := ArchiveImport{}
+	mustCall(t, server, "POST", "/vs/admin/import", archive, &result, http.StatusOK)
+	if result.Languages.Restored != 1 || result.Words.Restored != 1 || result.Definitions.Restored != 1 {
+		t.Fatalf("got %+v, want one of each restored", result)
+	}
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon", nil, nil, http.StatusOK)
+}
+
+func TestIncompleteArchivesAreRefused(t *testing.T) {
+	archive := exportTestArchive(t)
+	archive.Complete = false
+	server := newTestServer(t)
+
+	mustCall(t, server, "POST", "/vs/admin/import", archive, nil, http.StatusBadRequest)
+	mustCall(t, server, "GET", "/vs/languages/quenya", nil, nil, http.StatusNotFound)
+}
+
+func TestReplaceRefusesToDeleteUnarchivedRows(t *testing.T) {
+	archive := exportTestArchive(t)
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "sindarin"}, nil, http.StatusCreated)
+	text := map[string]string{"title": "greeting", "content": "mae govannen"}
+	mustCall(t, server, "POST", "/vs/languages/sindarin/texts", text, nil, http.StatusCreated)
+
+	mustCall(t, server, "POST", "/vs/admin/import?strategy=replace", archive, nil, http.StatusConflict)
+	mustCall(t, server, "GET", "/vs/languages/sindarin", nil, nil, http.StatusOK)
+
+	mustCall(t, server, "DELETE", "/vs/languages/sindarin/texts/"+firstTextID(t, server, "sindarin"), nil, nil, http.StatusNoContent)
+	mustCall(t, server, "POST", "/vs/admin/import?strategy=replace", archive, nil, http.StatusOK)
+	mustCall(t, server, "GET", "/vs/languages/sindarin", nil, nil, http.StatusNotFound)
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon", nil, nil, http.StatusOK)
+}
+
+func firstTextID(t *testing.T, server *httptest.Server, language string) string {
+	t.Helper()
+	texts := []Text{}
+	mustCall(t, server, "GET", "/vs/languages/"+language+"/texts", nil, &texts, http.StatusOK)
+	if len(texts) == 0 {
+		t.Fatalf("%s has no texts", language)
+	}
+	return texts[0].ID.String()
+}
+
+func TestMergeSkipsNameCollisions(t *testing.T) {
+	archive := exportTestArchive(t)
+	server := newTestServer(t)
+	// Another quenya, under another ID.
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+
+	mustCall(t, server, "POST", "/vs/admin/import?strategy=fail", archive, nil, http.StatusConflict)
+
+	result := ArchiveImport{}
+	mustCall(t, server, "POST", "/vs/admin/import?strategy=merge", archive, &result, http.StatusOK)
+	if result.Languages.Skipped != 1 || result.Words.Skipped != 1 || result.Definitions.Skipped != 1 {
+		t.Fatalf("got %+v, want the language and everything in it skipped", result)
+	}
+	if len(result.Skipped) != 3 || result.Skipped[0].Kind != "language" {
+		t.Fatalf("got skipped rows %+v", result.Skipped)
+	}
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon", nil, nil, http.StatusNotFound)
+
+	// A word spelled like one already in the language is skipped on its own.
+	server = newTestServer(t)
+	mustCall(t, server, "POST", "/vs/admin/import", Archive{
+		Format:    archive.Format,
+		Version:   archive.Version,
+		Languages: archive.Languages,
+		Complete:  true,
+	}, nil, http.StatusOK)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+
+	result = ArchiveImport{}
+	mustCall(t, server, "POST", "/vs/admin/import?strategy=merge", archive, &result, http.StatusOK)
+	if result.Languages.Unchanged != 1 || result.Words.Skipped != 1 || result.Definitions.Skipped != 1 {
+		t.Fatalf("got %+v, want the word and its definition skipped", result)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+/*
+ * Browse Pages
+ */
+
+// The read-only pages of the browse interface. The listing handlers render
+// these for clients that ask for HTML, from the same data they would send as
+// JSON, so the interface can never show something the API does not.
+var browseTemplates = template.Must(template.New("browse").Funcs(template.FuncMap{
+	"path": url.PathEscape,
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.}}</title>
+<style>
+body { font-family: Georgia, serif; margin: 2em auto; max-width: 45em; padding: 0 1em; line-height: 1.5; }
+nav { border-bottom: 1px solid #999; margin-bottom: 1.5em; }
+ul.words { columns: 3; list-style: none; padding: 0; }
+.part-of-speech, .example-source { font-style: italic; }
+.rank { color: #555; }
+.error { color: #b00020; }
+form.logout { display: inline; float: right; }
+.definition { border-left: 3px solid #ccc; padding-left: 0.75em; margin-bottom: 1em; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+
+{{define "next"}}{{if .}}<p><a href="{{.}}">Next page</a></p>{{end}}{{end}}
+
+{{define "languages"}}{{template "header" "Languages"}}<h1>Languages</h1>
+<ul>
+{{range .Languages}}<li><a href="/languages/{{path .Name}}">{{.Name}}</a></li>
+{{else}}<li>No languages yet.</li>
+{{end}}</ul>
+{{template "next" .Next}}
+{{template "footer"}}{{end}}
+
+{{define "words"}}{{template "header" .Language.Name}}<nav><a href="/">Languages</a></nav>
+<h1>{{.Language.Name}}</h1>
+<ul class="words">
+{{range .Words}}<li><a href="/languages/{{path $.Language.Name}}/words/{{path .Word}}">{{.Word}}</a>{{if .FontFormatted}} <span class="font-formatted">{{.FontFormatted}}</span>{{end}}</li>
+{{else}}<li>No words yet.</li>
+{{end}}</ul>
+{{template "next" .Next}}
+{{template "footer"}}{{end}}
+
+{{define "word"}}{{template "header" .Word.Word}}<nav><a href="/">Languages</a> › <a href="/languages/{{path .Language.Name}}">{{.Language.Name}}</a></nav>
+<h1>{{.Word.Word}}{{if .Word.FontFormatted}} <span class="font-formatted">{{.Word.FontFormatted}}</span>{{end}}</h1>
+{{if .Word.FrequencyRank}}<p class="rank">Ranked {{.Word.FrequencyRank}} by frequency in the corpus.</p>{{end}}
+<ol>
+{{range .Word.Definitions}}<li><span class="part-of-speech">{{.PartOfSpeech}}</span> {{.Content}}</li>
+{{else}}<li>No definitions yet.</li>
+{{end}}</ol>
+{{if .Word.Examples}}<h2>Examples</h2>
+<ul>
+{{range .Word.Examples}}<li><span class="example-source">{{.Source}}</span>{{if .Translation}} ‘{{.Translation}}’{{end}}</li>
+{{end}}</ul>
+{{end}}
+{{template "footer"}}{{end}}
+
+{{define "error"}}{{template "header" "Error"}}<nav><a href="/">Languages</a></nav>
+<h1>{{.Status}}</h1>
+<p class="error">{{.Message}}</p>
+{{template "footer"}}{{end}}
+`))
+
+// Renders a browse page. Rendering goes straight to the response, so a
+// failure part way through can only be logged.
+func renderBrowsePage(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := browseTemplates.ExecuteTemplate(w, name, data); err != nil {
+		log.Printf("Error rendering %s page: %s", name, err)
+	}
+}
+
+// Responds with an error page to clients browsing in HTML, and with the usual
+// JSON error to everyone else.
+func respondBrowseError(msg string, w http.ResponseWriter, r *http.Request, status int) {
+	if !wantsHTML(r) {
+		respondError(msg, w, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	err := browseTemplates.ExecuteTemplate(w, "error", struct {
+		Status  string
+		Message string
+	}{http.StatusText(status), msg})
+	if err != nil {
+		log.Printf("Error rendering error page: %s", err)
+	}
+}
+
+// The link to the page after the current one, keeping the request's other
+// query parameters. It is empty on the last page.
+func nextPageLink(r *http.Request, next string) string {
+	if next == "" {
+		return ""
+	}
+	query := r.URL.Query()
+	query.Set("cursor", next)
+	return r.URL.Path + "?" + query.Encode()
+}
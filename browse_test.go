@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBrowsePagesNegotiateContent(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+
+	tests := []struct {
+		path   string
+		accept string
+		status int
+		html   bool
+	}{
+		{"/", "text/html", http.StatusOK, true},
+		{"/languages/quenya", "text/html", http.StatusOK, true},
+		{"/languages/quenya/words/mellon", "text/html", http.StatusOK, true},
+		{"/languages/quenya/words/mellon", "text/html,application/xhtml+xml,*/*;q=0.8", http.StatusOK, true},
+		{"/languages/quenya/words/mellon", "application/json, text/html;q=0.1", http.StatusOK, false},
+		{"/languages/quenya/words/mellon", "*/*", http.StatusOK, false},
+		{"/languages/sindarin", "text/html", http.StatusNotFound, true},
+		{"/languages/quenya/words/meldo", "text/html", http.StatusNotFound, true},
+		{"/vs/languages/quenya/words/meldo", "application/json", http.StatusNotFound, false},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", server.URL+tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", tt.accept)
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if res.StatusCode != tt.status {
+			t.Errorf("%s (%s): got status %d, want %d", tt.path, tt.accept, res.StatusCode, tt.status)
+		}
+		if html := strings.HasPrefix(res.Header.Get("Content-Type"), "text/html"); html != tt.html {
+			t.Errorf("%s (%s): got HTML %t, want %t:\n%s", tt.path, tt.accept, html, tt.html, body)
+		}
+		if got := res.Header.Get("Vary"); got != "Accept" {
+			t.Errorf("%s: got Vary %q, want Accept", tt.path, got)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"vastestsea/internal/corpus"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Concordance Handlers
+ */
+
+const (
+	defaultConcordanceContext = 5
+	maxConcordanceContext     = 50
+)
+
+// How often each token form occurs across the texts of each language, kept
+// between requests so that listing words doesn't tokenize every text again.
+// Text writes invalidate the counts of their language. Word writes need not,
+// as the counts are linked to the lexicon afresh on every read.
+type tokenCounts struct {
+	mu     sync.Mutex
+	counts map[uuid.UUID]map[string]int
+	// Bumped on every invalidation, so that counts read from texts that have
+	// since changed are not cached.
+	generations map[uuid.UUID]int
+}
+
+func newTokenCounts() *tokenCounts {
+	return &tokenCounts{
+		counts:      map[uuid.UUID]map[string]int{},
+		generations: map[uuid.UUID]int{},
+	}
+}
+
+// Forgets the counts of a language, whose texts have changed.
+func (tc *tokenCounts) invalidate(languageID uuid.UUID) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	delete(tc.counts, languageID)
+	tc.generations[languageID]++
+}
+
+// The token counts of a language, which callers must not modify.
+func (cfg *apiConfig) getTokenCounts(ctx context.Context, languageID uuid.UUID) (map[string]int, error) {
+	tc := cfg.tokenCounts
+	tc.mu.Lock()
+	counts, ok := tc.counts[languageID]
+	generation := tc.generations[languageID]
+	tc.mu.Unlock()
+	if ok {
+		return counts, nil
+	}
+
+	texts, err := cfg.store.GetTextsOfLanguage(ctx, languageID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts = map[string]int{}
+	for _, text := range texts {
+		for form, count := range corpus.Count(corpus.Tokenize(text.Content)) {
+			counts[form] += count
+		}
+	}
+
+	tc.mu.Lock()
+	if tc.generations[languageID] == generation {
+		tc.counts[languageID] = counts
+	}
+	tc.mu.Unlock()
+	return counts, nil
+}
+
+// Counts how often each word of a language occurs across its texts, and ranks
+// the words that occur at all. Tokens that match no word are not ranked.
+func (cfg *apiConfig) getWordFrequencies(ctx context.Context, languageID uuid.UUID) ([]WordFrequency, error) {
+	counts, err := cfg.getTokenCounts(ctx, languageID)
+	if err != nil {
+		return nil, err
+	}
+
+	forms := []string{}
+	for form := range counts {
+		forms = append(forms, form)
+	}
+	words, err := cfg.store.GetWordsFromLanguageByForms(ctx, database.GetWordsFromLanguageByFormsParams{
+		LanguageID: languageID,
+		Forms:      forms,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	linked := map[string]database.Word{}
+	linkedCounts := map[string]int{}
+	for _, word := range words {
+		form := strings.ToLower(word.Word)
+		linked[form] = word
+		linkedCounts[form] = counts[form]
+	}
+
+	frequencies := []WordFrequency{}
+	for _, rank := range corpus.RankCounts(linkedCounts) {
+		word := linked[rank.Form]
+		frequencies = append(frequencies, WordFrequency{
+			WordID: word.ID,
+			Word:   word.Word,
+			Count:  rank.Count,
+			Rank:   rank.Rank,
+		})
+	}
+	return frequencies, nil
+}
+
+// The frequency rank of each word of a language that occurs in its texts.
+func (cfg *apiConfig) getFrequencyRanks(ctx context.Context, languageID uuid.UUID) (map[uuid.UUID]int, error) {
+	frequencies, err := cfg.getWordFrequencies(ctx, languageID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := map[uuid.UUID]int{}
+	for _, f := range frequencies {
+		ranks[f.WordID] = f.Rank
+	}
+	return ranks, nil
+}
+
+// The frequency ranks of a language's words, when the request asks for them
+// with `include=frequency`. Ranks are best effort: the dictionary is still
+// served when the corpus can't be counted, only without them.
+func (cfg *apiConfig) getRequestedFrequencyRanks(r *http.Request, languageID uuid.UUID) map[uuid.UUID]int {
+	if r.URL.Query().Get("include") != "frequency" {
+		return nil
+	}
+
+	ranks, err := cfg.getFrequencyRanks(r.Context(), languageID)
+	if err != nil {
+		log.Printf("Error counting word frequencies: %s", err)
+		return nil
+	}
+	return ranks
+}
+
+// Get the words of the language in the path parameter ranked by how often
+// they occur in its texts, most frequent first.
+func (cfg *apiConfig) getFrequencies(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	frequencies, err := cfg.getWordFrequencies(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to count word frequencies", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(frequencies, w, http.StatusOK)
+}
+
+// Get every occurrence of the word given in the path parameters across the
+// texts of its language, keyword in context. The `context` query parameter
+// sets how many tokens to show on either side.
+func (cfg *apiConfig) getConcordance(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	width := defaultConcordanceContext
+	if value := r.URL.Query().Get("context"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > maxConcordanceContext {
+			respondError("Invalid context: must be between 0 and 50", w, http.StatusBadRequest)
+			return
+		}
+		width = n
+	}
+
+	texts, err := cfg.store.GetTextsOfLanguage(r.Context(), word.LanguageID)
+	if err != nil {
+		respondError("Failed to retrieve texts", w, http.StatusInternalServerError)
+		return
+	}
+
+	concordance := Concordance{
+		WordID:      word.ID,
+		Word:        word.Word,
+		Context:     width,
+		Occurrences: []ConcordanceLine{},
+	}
+	for _, text := range texts {
+		tokens := corpus.Tokenize(text.Content)
+		for _, o := range corpus.KWIC(text.Content, tokens, word.Word, width) {
+			concordance.Occurrences = append(concordance.Occurrences, getConcordanceLine(text, o))
+		}
+	}
+
+	writeResponse(concordance, w, http.StatusOK)
+}
+
+func getConcordanceLine(text database.Text, o corpus.Occurrence) ConcordanceLine {
+	return ConcordanceLine{
+		TextID:     text.ID,
+		Title:      text.Title,
+		Occurrence: o,
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFrequencyRanksFollowTextWrites(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "meldo"}, nil, http.StatusCreated)
+
+	rank := func(word string) int {
+		t.Helper()
+		got := Word{}
+		mustCall(t, server, "GET", "/vs/languages/quenya/words/"+word+"?include=frequency", nil, &got, http.StatusOK)
+		return got.FrequencyRank
+	}
+
+	analysis := TextAnalysis{}
+	text := map[string]string{"title": "greeting", "content": "mellon mellon meldo"}
+	mustCall(t, server, "POST", "/vs/languages/quenya/texts", text, &analysis, http.StatusCreated)
+	if rank("mellon") != 1 || rank("meldo") != 2 {
+		t.Fatalf("got ranks %d and %d, want 1 and 2", rank("mellon"), rank("meldo"))
+	}
+
+	path := "/vs/languages/quenya/texts/" + analysis.Text.ID.String()
+	text["content"] = "meldo meldo mellon"
+	mustCall(t, server, "PUT", path, text, nil, http.StatusOK)
+	if rank("mellon") != 2 || rank("meldo") != 1 {
+		t.Fatalf("after an update, got ranks %d and %d, want 2 and 1", rank("mellon"), rank("meldo"))
+	}
+
+	// Words added since the texts were counted are ranked too.
+	mustCall(t, server, "POST", "/vs/languages/quenya/texts", map[string]string{"title": "stranger", "content": "elen"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "elen"}, nil, http.StatusCreated)
+	if rank("elen") != 2 {
+		t.Fatalf("got rank %d for a new word, want 2", rank("elen"))
+	}
+
+	mustCall(t, server, "DELETE", path, nil, nil, http.StatusNoContent)
+	if rank("mellon") != 0 || rank("elen") != 1 {
+		t.Fatalf("after a delete, got ranks %d and %d, want 0 and 1", rank("mellon"), rank("elen"))
+	}
+}
+
+func TestFrequencyRanksAreOptIn(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/texts", map[string]string{"title": "greeting", "content": "mellon"}, nil, http.StatusCreated)
+
+	word := Word{}
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/mellon", nil, &word, http.StatusOK)
+	if word.FrequencyRank != 0 {
+		t.Errorf("got rank %d without include=frequency, want none", word.FrequencyRank)
+	}
+
+	page := struct{ Data []Word }{}
+	mustCall(t, server, "GET", "/vs/languages/quenya/words?include=frequency", nil, &page, http.StatusOK)
+	if len(page.Data) != 1 || page.Data[0].FrequencyRank != 1 {
+		t.Errorf("got %+v, want mellon ranked 1", page.Data)
+	}
+}
@@ -0,0 +1,469 @@
+package main
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/store"
+)
+
+/*
+ * Spreadsheet Handlers
+ */
+
+// How an import treats words that already exist in the language.
+const (
+	importModeSkip   = "skip"
+	importModeUpsert = "upsert"
+)
+
+// Returned from a dry run's transaction so that it is rolled back.
+var errDryRun = errors.New("dry run")
+
+// The header of the column each field is read from. Headers are matched
+// without regard to case.
+type columnMapping struct {
+	Word          string
+	FontFormatted string
+	PartOfSpeech  string
+	Definition    string
+}
+
+// Reads the column mapping from the query parameters, defaulting each column
+// to the name of its field.
+func getColumnMapping(query url.Values) columnMapping {
+	column := func(field string) string {
+		if header := strings.TrimSpace(query.Get(field)); header != "" {
+			return header
+		}
+		return field
+	}
+
+	return columnMapping{
+		Word:          column("word"),
+		FontFormatted: column("font_formatted"),
+		PartOfSpeech:  column("part_of_speech"),
+		Definition:    column("definition"),
+	}
+}
+
+// The position of each mapped column in a header row, or -1 for those the
+// file does not have.
+type columnIndices struct {
+	word, fontFormatted, partOfSpeech, definition int
+}
+
+// Finds the mapped columns in the header. Only the word column is required,
+// along with any other column whose header was given explicitly. Returns the
+// headers of the columns that are not mapped.
+func (m columnMapping) find(header []string, query url.Values) (columnIndices, []string, error) {
+	indices := columnIndices{-1, -1, -1, -1}
+	ignored := []string{}
+
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		switch {
+		case strings.EqualFold(h, m.Word):
+			indices.word = i
+		case strings.EqualFold(h, m.FontFormatted):
+			indices.fontFormatted = i
+		case strings.EqualFold(h, m.PartOfSpeech):
+			indices.partOfSpeech = i
+		case strings.EqualFold(h, m.Definition):
+			indices.definition = i
+		default:
+			ignored = append(ignored, h)
+		}
+	}
+
+	required := []struct {
+		field, header string
+		index         int
+	}{
+		{"word", m.Word, indices.word},
+		{"font_formatted", m.FontFormatted, indices.fontFormatted},
+		{"part_of_speech", m.PartOfSpeech, indices.partOfSpeech},
+		{"definition", m.Definition, indices.definition},
+	}
+	for _, c := range required {
+		if c.index < 0 && (c.field == "word" || query.Get(c.field) != "") {
+			return columnIndices{}, nil, fmt.Errorf("no %q column for %s", c.header, c.field)
+		}
+	}
+	if (indices.partOfSpeech < 0) != (indices.definition < 0) {
+		return columnIndices{}, nil, fmt.Errorf("part_of_speech and definition columns must be given together")
+	}
+
+	return indices, ignored, nil
+}
+
+// A data row of a spreadsheet, read through the column mapping.
+type spreadsheetRow struct {
+	Line          int
+	Word          string
+	FontFormatted string
+	PartOfSpeech  string
+	Definition    string
+}
+
+func (c columnIndices) read(record []string, line int) spreadsheetRow {
+	cell := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	return spreadsheetRow{
+		Line:          line,
+		Word:          cell(c.word),
+		FontFormatted: cell(c.fontFormatted),
+		PartOfSpeech:  cell(c.partOfSpeech),
+		Definition:    cell(c.definition),
+	}
+}
+
+func (row spreadsheetRow) validate() error {
+	if row.Word == "" {
+		return fmt.Errorf("word is empty")
+	}
+	if (row.PartOfSpeech == "") != (row.Definition == "") {
+		return fmt.Errorf("part_of_speech and definition must be given together")
+	}
+	return nil
+}
+
+// Whether the request body is tab-separated, from the `format` query
+// parameter or, failing that, the Content-Type.
+func isTSV(r *http.Request) (bool, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "csv":
+		return false, nil
+	case "tsv":
+		return true, nil
+	case "":
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		return mediaType == "text/tab-separated-values", nil
+	default:
+		return false, fmt.Errorf("format must be csv or tsv")
+	}
+}
+
+// Import words and definitions from a CSV or TSV spreadsheet into the
+// language in the path parameter, creating the language if it does not
+// exist. The first row holds the column headers, which the `word`,
+// `font_formatted`, `part_of_speech` and `definition` query parameters map to
+// fields. Each row adds a word, and a definition if it has one, so a word
+// with several definitions spans several rows.
+//
+// In `skip` mode, the default, rows of words that already exist are skipped.
+// In `upsert` mode they update the word's font-formatted form, and the part
+// of speech of a definition it already has. Rows that cannot be imported are
+// reported by line, and the rest are imported in a single transaction. With
+// `dry_run`, the import is rolled back once the report is complete.
+func (cfg *apiConfig) importSpreadsheet(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	query := r.URL.Query()
+
+	mode := query.Get("mode")
+	if mode == "" {
+		mode = importModeSkip
+	}
+	if mode != importModeSkip && mode != importModeUpsert {
+		respondError("Invalid mode: must be skip or upsert", w, http.StatusBadRequest)
+		return
+	}
+
+	dryRun := false
+	if value := query.Get("dry_run"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			respondError("Invalid dry_run: must be true or false", w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	tsv, err := isTSV(r)
+	if err != nil {
+		respondError(fmt.Sprintf("Invalid format: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	reader := csv.NewReader(r.Body)
+	reader.FieldsPerRecord = -1
+	if tsv {
+		reader.Comma = '\t'
+		reader.LazyQuotes = true
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		respondError(fmt.Sprintf("Could not read header row: %s", err), w, http.StatusBadRequest)
+		return
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	indices, ignored, err := getColumnMapping(query).find(header, query)
+	if err != nil {
+		respondError(fmt.Sprintf("Invalid column mapping: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	result := SpreadsheetImport{
+		DryRun:         dryRun,
+		Mode:           mode,
+		IgnoredColumns: ignored,
+		Skipped:        []RowReport{},
+		Errors:         []RowReport{},
+	}
+
+	rows := []spreadsheetRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			respondError(fmt.Sprintf("Could not parse spreadsheet: %s", err), w, http.StatusBadRequest)
+			return
+		}
+		line, _ := reader.FieldPos(0)
+		result.Rows++
+
+		row := indices.read(record, line)
+		if err := row.validate(); err != nil {
+			result.Errors = append(result.Errors, RowReport{Line: line, Word: row.Word, Reason: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		language, err := tx.GetLanguage(r.Context(), strings.ToLower(languageName))
+		if err != nil {
+			language, err = tx.CreateLanguage(r.Context(), languageName)
+			if err != nil {
+				return stepError("create language", err, getFailedCreationCode(err))
+			}
+		}
+		result.Language = getMarshallableLanguage(language)
+
+		importer := spreadsheetImporter{
+			tx:       tx,
+			language: language,
+			mode:     mode,
+			result:   &result,
+			words:    map[string]*importedRowWord{},
+		}
+		for _, row := range rows {
+			if err := importer.importRow(r, row); err != nil {
+				return err
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		respondTxError(err, w)
+		return
+	}
+
+	// Rows that fail validation are reported before the import runs.
+	slices.SortStableFunc(result.Errors, func(a, b RowReport) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+
+	writeResponse(result, w, http.StatusOK)
+}
+
+// A word touched by an import, whether it existed before, and whether the
+// import has updated it.
+type importedRowWord struct {
+	word        database.Word
+	existed     bool
+	updated     bool
+	definitions map[string]database.Definition
+}
+
+// Imports spreadsheet rows within a transaction, remembering the words it
+// has seen so that rows of the same word build on each other.
+type spreadsheetImporter struct {
+	tx       store.Store
+	language database.Language
+	mode     string
+	result   *SpreadsheetImport
+	words    map[string]*importedRowWord
+}
+
+func (im *spreadsheetImporter) getWord(r *http.Request, row spreadsheetRow) (*importedRowWord, error) {
+	key := strings.ToLower(row.Word)
+	if word, ok := im.words[key]; ok {
+		return word, nil
+	}
+
+	imported := &importedRowWord{definitions: map[string]database.Definition{}}
+	word, err := im.tx.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+		Word:       key,
+		LanguageID: im.language.ID,
+	})
+	switch {
+	case err == nil:
+		imported.existed = true
+		definitions, err := im.tx.GetDefinitionsOfWord(r.Context(), word.ID)
+		if err != nil {
+			return nil, stepError(fmt.Sprintf("get definitions of %q on line %d", row.Word, row.Line), err, http.StatusInternalServerError)
+		}
+		for _, definition := range definitions {
+			imported.definitions[definition.Content] = definition
+		}
+	case errors.Is(err, sql.ErrNoRows):
+		word, err = im.tx.CreateFormattedWord(r.Context(), database.CreateFormattedWordParams{
+			Word:          row.Word,
+			FontFormatted: sql.NullString{String: row.FontFormatted, Valid: row.FontFormatted != ""},
+			LanguageID:    im.language.ID,
+		})
+		if err != nil {
+			return nil, stepError(fmt.Sprintf("create word %q on line %d", row.Word, row.Line), err, getFailedCreationCode(err))
+		}
+		im.result.WordsCreated++
+	default:
+		return nil, stepError(fmt.Sprintf("get word %q on line %d", row.Word, row.Line), err, http.StatusInternalServerError)
+	}
+
+	imported.word = word
+	im.words[key] = imported
+	return imported, nil
+}
+
+func (im *spreadsheetImporter) report(list *[]RowReport, row spreadsheetRow, reason string) {
+	*list = append(*list, RowReport{Line: row.Line, Word: row.Word, Reason: reason})
+}
+
+func (im *spreadsheetImporter) importRow(r *http.Request, row spreadsheetRow) error {
+	word, err := im.getWord(r, row)
+	if err != nil {
+		return err
+	}
+
+	if word.existed && im.mode == importModeSkip {
+		im.report(&im.result.Skipped, row, "word already exists")
+		return nil
+	}
+
+	formatted := word.word.FontFormatted.String
+	if row.FontFormatted != "" && row.FontFormatted != formatted {
+		if !word.existed && word.word.FontFormatted.Valid && im.mode == importModeSkip {
+			im.report(&im.result.Errors, row, fmt.Sprintf("font_formatted conflicts with %q from an earlier row", formatted))
+			return nil
+		}
+
+		word.word, err = im.tx.UpdateWord(r.Context(), database.UpdateWordParams{
+			SetFormatted: true,
+			Formatted:    row.FontFormatted,
+			ID:           word.word.ID,
+		})
+		if err != nil {
+			return stepError(fmt.Sprintf("update word %q on line %d", row.Word, row.Line), err, http.StatusInternalServerError)
+		}
+		if word.existed && !word.updated {
+			word.updated = true
+			im.result.WordsUpdated++
+		}
+	}
+
+	if row.Definition == "" {
+		return nil
+	}
+
+	if definition, ok := word.definitions[row.Definition]; ok {
+		if definition.PartOfSpeech == row.PartOfSpeech {
+			im.report(&im.result.Skipped, row, "definition already exists")
+			return nil
+		}
+		if im.mode == importModeSkip {
+			im.report(&im.result.Errors, row, fmt.Sprintf("definition already exists as %s", definition.PartOfSpeech))
+			return nil
+		}
+
+		definition, err = im.tx.UpdateDefinitionPartOfSpeech(r.Context(), database.UpdateDefinitionPartOfSpeechParams{
+			PartOfSpeech: row.PartOfSpeech,
+			ID:           definition.ID,
+		})
+		if err != nil {
+			return stepError(fmt.Sprintf("update definition of %q on line %d", row.Word, row.Line), err, http.StatusInternalServerError)
+		}
+		word.definitions[row.Definition] = definition
+		im.result.DefinitionsUpdated++
+		return nil
+	}
+
+	definition, err := im.tx.CreateDefinition(r.Context(), database.CreateDefinitionParams{
+		Content:      row.Definition,
+		PartOfSpeech: row.PartOfSpeech,
+		WordID:       word.word.ID,
+	})
+	if err != nil {
+		return stepError(fmt.Sprintf("create definition of %q on line %d", row.Word, row.Line), err, getFailedCreationCode(err))
+	}
+	word.definitions[row.Definition] = definition
+	im.result.DefinitionsCreated++
+	return nil
+}
+
+// Export the words and definitions of the language in the path parameter as
+// CSV, one row per definition, with the same columns imports read. Words
+// without definitions get a row of their own. The column headers can be
+// renamed with the same query parameters as imports.
+func (cfg *apiConfig) exportSpreadsheet(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	lexicon, err := cfg.getLexicon(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve words", w, http.StatusInternalServerError)
+		return
+	}
+
+	columns := getColumnMapping(r.URL.Query())
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.ToLower(language.Name)+".csv"))
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{columns.Word, columns.FontFormatted, columns.PartOfSpeech, columns.Definition})
+	for _, entry := range lexicon {
+		formatted := entry.Word.FontFormatted.String
+		if len(entry.Definitions) == 0 {
+			writer.Write([]string{entry.Word.Word, formatted, "", ""})
+			continue
+		}
+		for _, definition := range entry.Definitions {
+			writer.Write([]string{entry.Word.Word, formatted, definition.PartOfSpeech, definition.Content})
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Error writing CSV export: %s", err)
+	}
+}
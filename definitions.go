@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Definition Handlers
+ */
+
+type definitionParams struct {
+	Content      string `json:"content"`
+	PartOfSpeech string `json:"part_of_speech"`
+}
+
+// Resolves the word identified by the `language` and `word` path parameters.
+// Writes a not found response and returns false if either does not exist.
+func (cfg *apiConfig) getWordFromPath(w http.ResponseWriter, r *http.Request) (database.Word, bool) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return database.Word{}, false
+	}
+
+	wordName := r.PathValue("word")
+	word, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+		Word:       strings.ToLower(wordName),
+		LanguageID: language.ID,
+	})
+	if err != nil {
+		respondError("Word not found", w, http.StatusNotFound)
+		return database.Word{}, false
+	}
+
+	return word, true
+}
+
+// Resolves the definition identified by the `id` path parameter, ensuring it
+// belongs to the given word. Writes the appropriate error response and returns
+// false on failure.
+func (cfg *apiConfig) getDefinitionFromPath(
+	w http.ResponseWriter,
+	r *http.Request,
+	word database.Word,
+) (database.Definition, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError("Invalid definition ID", w, http.StatusBadRequest)
+		return database.Definition{}, false
+	}
+
+	definition, err := cfg.store.GetDefinitionByID(r.Context(), id)
+	if err != nil || definition.WordID != word.ID {
+		respondError("Definition not found", w, http.StatusNotFound)
+		return database.Definition{}, false
+	}
+
+	return definition, true
+}
+
+// Get all definitions of the word given in the path parameters.
+func (cfg *apiConfig) getDefinitions(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	definitions, err := cfg.store.GetDefinitionsOfWord(r.Context(), word.ID)
+	if err != nil {
+		respondError("Failed to retrieve definitions", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(getMarshallableDefinitions(definitions), w, http.StatusOK)
+}
+
+// Get a single definition of the word given in the path parameters.
+func (cfg *apiConfig) getDefinition(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	definition, ok := cfg.getDefinitionFromPath(w, r, word)
+	if !ok {
+		return
+	}
+
+	writeResponse(getMarshallableDefinition(definition), w, http.StatusOK)
+}
+
+// Create one or more definitions for the word given in the path parameters.
+// The body may be a single definition object, or an array of them, so that
+// several senses can be added in one request.
+func (cfg *apiConfig) createDefinitions(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params, err := decodeOneOrMany[definitionParams](r.Body)
+	if err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if len(params) == 0 {
+		respondError("Invalid request body", w, http.StatusBadRequest)
+		return
+	}
+	for _, p := range params {
+		if p.Content == "" || p.PartOfSpeech == "" {
+			respondError("Invalid request body", w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Either every definition in the request is created, or none are.
+	created := []database.Definition{}
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		for i, p := range params {
+			definition, err := tx.CreateDefinition(r.Context(), database.CreateDefinitionParams{
+				WordID:       word.ID,
+				Content:      p.Content,
+				PartOfSpeech: p.PartOfSpeech,
+			})
+			if err != nil {
+				return stepError(fmt.Sprintf("create definition %d", i), err, getFailedCreationCode(err))
+			}
+			created = append(created, definition)
+		}
+		return nil
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	writeResponse(getMarshallableDefinitions(created), w, http.StatusCreated)
+}
+
+// Partially update a definition. Only the fields present in the body are
+// changed.
+func (cfg *apiConfig) updateDefinition(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	definition, ok := cfg.getDefinitionFromPath(w, r, word)
+	if !ok {
+		return
+	}
+
+	params := definitionParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	switch {
+	case params.Content != "" && params.PartOfSpeech != "":
+		definition, err = cfg.store.UpdateDefinition(r.Context(), database.UpdateDefinitionParams{
+			Content:      params.Content,
+			PartOfSpeech: params.PartOfSpeech,
+			ID:           definition.ID,
+		})
+	case params.Content != "":
+		definition, err = cfg.store.UpdateDefinitionContent(r.Context(), database.UpdateDefinitionContentParams{
+			Content: params.Content,
+			ID:      definition.ID,
+		})
+	case params.PartOfSpeech != "":
+		definition, err = cfg.store.UpdateDefinitionPartOfSpeech(r.Context(), database.UpdateDefinitionPartOfSpeechParams{
+			PartOfSpeech: params.PartOfSpeech,
+			ID:           definition.ID,
+		})
+	default:
+		respondError("Invalid request body", w, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		respondError(
+			fmt.Sprintf("Failed to update definition: %s", err),
+			w,
+			getFailedCreationCode(err),
+		)
+		return
+	}
+
+	writeResponse(getMarshallableDefinition(definition), w, http.StatusOK)
+}
+
+// Delete a definition of the word given in the path parameters.
+func (cfg *apiConfig) deleteDefinition(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	definition, ok := cfg.getDefinitionFromPath(w, r, word)
+	if !ok {
+		return
+	}
+
+	if err := cfg.store.DeleteDefinition(r.Context(), definition.ID); err != nil {
+		respondError("Failed to delete definition", w, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/printing"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Printed Dictionary Handlers
+ */
+
+// Lays out every word of a language as a printed dictionary.
+func (cfg *apiConfig) getPrintedDictionary(ctx context.Context, language database.Language) (printing.Dictionary, error) {
+	lexicon, err := cfg.getLexicon(ctx, language.ID)
+	if err != nil {
+		return printing.Dictionary{}, err
+	}
+
+	entries := []printing.Entry{}
+	for _, lexiconEntry := range lexicon {
+		entries = append(entries, getPrintedEntry(lexiconEntry))
+	}
+
+	return printing.New(language.Name, entries), nil
+}
+
+// Lays out a word as a printed dictionary entry, with a sense for each of its
+// definitions.
+func getPrintedEntry(lexiconEntry lexiconEntry) printing.Entry {
+	entry := printing.Entry{
+		Headword: lexiconEntry.Word.Word,
+		Examples: getPrintedExamples(lexiconEntry.Examples[uuid.Nil]),
+	}
+	if lexiconEntry.Word.FontFormatted.Valid {
+		entry.FontFormatted = lexiconEntry.Word.FontFormatted.String
+	}
+	// Definitions come ordered by part of speech, so that each part of
+	// speech forms a single group.
+	for _, definition := range lexiconEntry.Definitions {
+		entry.Senses = append(entry.Senses, printing.Sense{
+			PartOfSpeech: definition.PartOfSpeech,
+			Definition:   definition.Content,
+			Examples:     getPrintedExamples(lexiconEntry.Examples[definition.ID]),
+		})
+	}
+	return entry
+}
+
+func getPrintedExamples(examples []database.Example) []printing.Example {
+	printed := []printing.Example{}
+	for _, e := range examples {
+		printed = append(printed, printing.Example{Source: e.Source, Translation: e.Translation})
+	}
+	return printed
+}
+
+// Renders the language in the path parameter with render, and sends it as a
+// file download. The dictionary is rendered in full before anything is
+// written, so that a failure can still be reported.
+func (cfg *apiConfig) exportDictionary(
+	w http.ResponseWriter,
+	r *http.Request,
+	extension string,
+	contentType string,
+	render func(printing.Dictionary, io.Writer) error,
+) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	dictionary, err := cfg.getPrintedDictionary(r.Context(), language)
+	if err != nil {
+		respondError("Failed to retrieve words", w, http.StatusInternalServerError)
+		return
+	}
+
+	buf := bytes.Buffer{}
+	if err := render(dictionary, &buf); err != nil {
+		log.Printf("Error rendering dictionary: %s", err)
+		respondError("Failed to render dictionary", w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.ToLower(language.Name)+"."+extension))
+	if _, err := buf.WriteTo(w); err != nil {
+		log.Printf("Error writing dictionary: %s", err)
+	}
+}
+
+// Render the language in the path parameter as a complete, alphabetized
+// dictionary in a standalone HTML page, ready to print.
+func (cfg *apiConfig) exportDictionaryHTML(w http.ResponseWriter, r *http.Request) {
+	cfg.exportDictionary(w, r, "html", "text/html; charset=utf-8", printing.Dictionary.WriteHTML)
+}
+
+// Render the language in the path parameter as a complete, alphabetized
+// dictionary in LaTeX source, to be compiled with XeLaTeX or LuaLaTeX.
+func (cfg *apiConfig) exportDictionaryLaTeX(w http.ResponseWriter, r *http.Request) {
+	cfg.exportDictionary(w, r, "tex", "application/x-tex; charset=utf-8", printing.Dictionary.WriteLaTeX)
+}
@@ -0,0 +1,605 @@
+package main
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"vastestsea/internal/auth"
+	"vastestsea/internal/database"
+	"vastestsea/internal/phonology"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Editor Handlers
+ */
+
+const editorLoginPath = "/edit/login"
+
+// The submitted values of a form, and the errors to show beside its fields.
+// An error under the empty name belongs to the form as a whole.
+type editorForm struct {
+	Values map[string]string
+	Errors map[string]string
+}
+
+func newEditorForm(values map[string]string) editorForm {
+	return editorForm{Values: values, Errors: map[string]string{}}
+}
+
+// Reads the named fields of a submitted form, trimming their whitespace.
+func readEditorForm(r *http.Request, names ...string) editorForm {
+	values := map[string]string{}
+	for _, name := range names {
+		values[name] = strings.TrimSpace(r.PostFormValue(name))
+	}
+	return newEditorForm(values)
+}
+
+func (f editorForm) Value(name string) string {
+	return f.Values[name]
+}
+
+func (f editorForm) Error(name string) string {
+	return f.Errors[name]
+}
+
+// Checks that each of the named fields has a value, as the API requires.
+func (f editorForm) require(labels map[string]string) {
+	for name, label := range labels {
+		if f.Values[name] == "" {
+			f.Errors[name] = label + " is required"
+		}
+	}
+}
+
+func (f editorForm) valid() bool {
+	return len(f.Errors) == 0
+}
+
+var editorTemplates = template.Must(template.Must(browseTemplates.Clone()).Parse(`
+{{define "csrf"}}<input type="hidden" name="csrf_token" value="{{.}}">{{end}}
+
+{{define "field-error"}}{{if .}} <span class="error">{{.}}</span>{{end}}{{end}}
+
+{{define "editor-nav"}}<nav><a href="/edit/">Languages</a>{{if .Language.Name}} › <a href="/edit/languages/{{path .Language.Name}}">{{.Language.Name}}</a>{{end}}
+<form class="logout" method="post" action="/edit/logout">{{template "csrf" .CSRFToken}}<button>Log out</button></form></nav>
+{{end}}
+
+{{define "login"}}{{template "header" "Log in"}}<h1>Log in to edit</h1>
+<form method="post" action="/edit/login">
+{{template "csrf" .CSRFToken}}<input type="hidden" name="next" value="{{.Next}}">
+{{with .Form.Error ""}}<p class="error">{{.}}</p>{{end}}
+<p><label>Password <input type="password" name="password" autofocus required></label></p>
+<p><button>Log in</button></p>
+</form>
+{{template "footer"}}{{end}}
+
+{{define "edit-languages"}}{{template "header" "Editor"}}{{template "editor-nav" .}}<h1>Languages</h1>
+<ul>
+{{range .Languages}}<li><a href="/edit/languages/{{path .Name}}">{{.Name}}</a></li>
+{{else}}<li>No languages yet.</li>
+{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "edit-words"}}{{template "header" .Language.Name}}{{template "editor-nav" .}}<h1>{{.Language.Name}}</h1>
+<h2>New word</h2>
+<form method="post" action="/edit/languages/{{path .Language.Name}}/words">
+{{template "csrf" .CSRFToken}}
+{{with .Form.Error ""}}<p class="error">{{.}}</p>{{end}}
+<p><label>Word <input name="word" value="{{.Form.Value "word"}}" required></label>{{template "field-error" .Form.Error "word"}}</p>
+<p><label>Font-formatted <input name="font_formatted" value="{{.Form.Value "font_formatted"}}"></label></p>
+<p><label><input type="checkbox" name="validate" value="true"{{if .Form.Value "validate"}} checked{{end}}> Check against the language's phonotactics</label></p>
+<p><button>Create word</button></p>
+</form>
+<h2>Words</h2>
+<ul class="words">
+{{range .Words}}<li><a href="/edit/languages/{{path $.Language.Name}}/words/{{path .Word}}">{{.Word}}</a></li>
+{{else}}<li>No words yet.</li>
+{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "definition-fields"}}<label>Part of speech <input name="part_of_speech" value="{{.Value "part_of_speech"}}" required></label>{{template "field-error" .Error "part_of_speech"}}
+<label>Definition <input name="content" value="{{.Value "content"}}" required></label>{{template "field-error" .Error "content"}}{{end}}
+
+{{define "edit-word"}}{{template "header" .Word.Word}}{{template "editor-nav" .}}<h1>{{.Word.Word}}</h1>
+{{$base := printf "/edit/languages/%s/words/%s" (path .Language.Name) (path .Word.Word)}}
+<form method="post" action="{{$base}}">
+{{template "csrf" .CSRFToken}}
+{{with .WordForm.Error ""}}<p class="error">{{.}}</p>{{end}}
+<p><label>Word <input name="word" value="{{.WordForm.Value "word"}}" required></label>{{template "field-error" .WordForm.Error "word"}}</p>
+<p><label>Font-formatted <input name="font_formatted" value="{{.WordForm.Value "font_formatted"}}"></label></p>
+<p><button>Save word</button></p>
+</form>
+<h2>Definitions</h2>
+{{range .Definitions}}<div class="definition">
+<form method="post" action="{{$base}}/definitions/{{.ID}}">
+{{template "csrf" $.CSRFToken}}
+{{with .Form.Error ""}}<p class="error">{{.}}</p>{{end}}
+{{template "definition-fields" .Form}}
+<button>Save</button>
+</form>
+<form method="post" action="{{$base}}/definitions/{{.ID}}/delete">{{template "csrf" $.CSRFToken}}<button>Delete</button></form>
+</div>
+{{else}}<p>No definitions yet.</p>
+{{end}}
+<h2>New definition</h2>
+<form method="post" action="{{$base}}/definitions">
+{{template "csrf" .CSRFToken}}
+{{with .AddForm.Error ""}}<p class="error">{{.}}</p>{{end}}
+{{template "definition-fields" .AddForm}}
+<button>Add definition</button>
+</form>
+{{template "footer"}}{{end}}
+`))
+
+// Renders an editor page. Rendering goes straight to the response, so a
+// failure part way through can only be logged.
+func renderEditorPage(w http.ResponseWriter, name string, status int, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := editorTemplates.ExecuteTemplate(w, name, data); err != nil {
+		log.Printf("Error rendering %s page: %s", name, err)
+	}
+}
+
+func editorWordPath(language database.Language, word database.Word) string {
+	return "/edit/languages/" + url.PathEscape(language.Name) + "/words/" + url.PathEscape(word.Word)
+}
+
+// Where to go after logging in. Only paths within the editor are allowed, so
+// that the login form cannot be used to send editors elsewhere.
+func editorNextPath(next string) string {
+	if !strings.HasPrefix(next, "/edit/") || strings.HasPrefix(next, "//") || strings.Contains(next, `\`) {
+		return "/edit/"
+	}
+	return next
+}
+
+// Show the editor's login form. No session exists until the login succeeds,
+// so the form's CSRF token comes from a cookie instead.
+func (cfg *apiConfig) editorLoginPage(w http.ResponseWriter, r *http.Request) {
+	next := editorNextPath(r.URL.Query().Get("next"))
+	if _, ok := cfg.auth.Sessions.Get(r); ok {
+		http.Redirect(w, r, next, http.StatusSeeOther)
+		return
+	}
+
+	renderEditorPage(w, "login", http.StatusOK, struct {
+		CSRFToken string
+		Next      string
+		Form      editorForm
+	}{cfg.auth.Sessions.LoginCSRFToken(w, r), next, newEditorForm(nil)})
+}
+
+// Log in with the editor password, starting a session, then continue to the
+// page that asked for it. Clients that fail too often are made to wait.
+func (cfg *apiConfig) editorLogin(w http.ResponseWriter, r *http.Request) {
+	next := editorNextPath(r.PostFormValue("next"))
+	form := newEditorForm(nil)
+
+	status := http.StatusOK
+	delay := cfg.auth.Sessions.LoginDelay(r)
+	switch {
+	case delay > 0:
+		w.Header().Set("Retry-After", strconv.Itoa(int(delay.Seconds())+1))
+		form.Errors[""] = "Too many failed attempts. Please try again later."
+		status = http.StatusTooManyRequests
+	case !cfg.auth.Sessions.CheckLoginCSRF(r):
+		// The form did not come from this site, or its cookie was lost.
+		form.Errors[""] = "Your login form expired. Please try again."
+		status = http.StatusForbidden
+	case cfg.auth.EditorPassword == "":
+		form.Errors[""] = "The editor is disabled, as no EDITOR_PASSWORD is set."
+		status = http.StatusServiceUnavailable
+	case !cfg.auth.CheckEditorPassword(r.PostFormValue("password")):
+		cfg.auth.Sessions.LoginFailed(r)
+		form.Errors[""] = "Incorrect password."
+		status = http.StatusUnauthorized
+	default:
+		cfg.auth.Sessions.LoginSucceeded(r)
+		cfg.auth.Sessions.LogIn(w, r)
+		http.Redirect(w, r, next, http.StatusSeeOther)
+		return
+	}
+
+	renderEditorPage(w, "login", status, struct {
+		CSRFToken string
+		Next      string
+		Form      editorForm
+	}{cfg.auth.Sessions.LoginCSRFToken(w, r), next, form})
+}
+
+func (cfg *apiConfig) editorLogout(w http.ResponseWriter, r *http.Request) {
+	cfg.auth.Sessions.LogOut(w, r)
+	http.Redirect(w, r, editorLoginPath, http.StatusSeeOther)
+}
+
+// List the languages to edit.
+func (cfg *apiConfig) editLanguages(w http.ResponseWriter, r *http.Request) {
+	languages, err := cfg.store.GetLanguages(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to retrieve languages", http.StatusInternalServerError)
+		return
+	}
+	slices.SortFunc(languages, func(a, b database.Language) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	marshallable := []Language{}
+	for _, language := range languages {
+		marshallable = append(marshallable, getMarshallableLanguage(language))
+	}
+
+	renderEditorPage(w, "edit-languages", http.StatusOK, struct {
+		CSRFToken string
+		Language  Language
+		Languages []Language
+	}{auth.SessionFromContext(r.Context()).CSRFToken, Language{}, marshallable})
+}
+
+// Finds the language in the path parameter, responding if it does not exist.
+func (cfg *apiConfig) getEditorLanguage(w http.ResponseWriter, r *http.Request) (database.Language, bool) {
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(r.PathValue("language")))
+	if err != nil {
+		http.Error(w, "Language not found", http.StatusNotFound)
+		return database.Language{}, false
+	}
+	return language, true
+}
+
+// Finds the language and word in the path parameters, responding if either
+// does not exist.
+func (cfg *apiConfig) getEditorWord(w http.ResponseWriter, r *http.Request) (database.Language, database.Word, bool) {
+	language, ok := cfg.getEditorLanguage(w, r)
+	if !ok {
+		return database.Language{}, database.Word{}, false
+	}
+
+	word, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+		Word:       strings.ToLower(r.PathValue("word")),
+		LanguageID: language.ID,
+	})
+	if err != nil {
+		http.Error(w, "Word not found", http.StatusNotFound)
+		return database.Language{}, database.Word{}, false
+	}
+	return language, word, true
+}
+
+// Renders a language's words with the new word form, which carries the
+// values and errors of a failed submission, if any.
+func (cfg *apiConfig) renderEditWords(w http.ResponseWriter, r *http.Request, language database.Language, form editorForm, status int) {
+	words, err := cfg.store.GetWordsByLanguageID(r.Context(), language.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve words", http.StatusInternalServerError)
+		return
+	}
+	slices.SortFunc(words, func(a, b database.Word) int {
+		return cmp.Or(
+			strings.Compare(strings.ToLower(a.Word), strings.ToLower(b.Word)),
+			strings.Compare(a.Word, b.Word),
+		)
+	})
+
+	marshallable := []Word{}
+	for _, word := range words {
+		marshallable = append(marshallable, getMarshallableWord(word, []database.Definition{}))
+	}
+
+	renderEditorPage(w, "edit-words", status, struct {
+		CSRFToken string
+		Language  Language
+		Words     []Word
+		Form      editorForm
+	}{auth.SessionFromContext(r.Context()).CSRFToken, getMarshallableLanguage(language), marshallable, form})
+}
+
+// List the words of the language in the path parameter, with a form for
+// adding another.
+func (cfg *apiConfig) editWords(w http.ResponseWriter, r *http.Request) {
+	language, ok := cfg.getEditorLanguage(w, r)
+	if !ok {
+		return
+	}
+	cfg.renderEditWords(w, r, language, newEditorForm(nil), http.StatusOK)
+}
+
+// Create a word from the new word form, optionally checking it against the
+// language's phonotactics first, as the API's `validate` flag does.
+func (cfg *apiConfig) editorCreateWord(w http.ResponseWriter, r *http.Request) {
+	language, ok := cfg.getEditorLanguage(w, r)
+	if !ok {
+		return
+	}
+
+	form := readEditorForm(r, "word", "font_formatted", "validate")
+	form.require(map[string]string{"word": "Word"})
+	if form.valid() && form.Value("validate") != "" {
+		if err := validateHeadword(r.Context(), cfg.store, language.ID, form.Value("word")); err != nil {
+			var validationErr *phonology.ValidationError
+			switch {
+			case errors.As(err, &validationErr):
+				form.Errors["word"] = fmt.Sprintf(
+					"Word does not fit the language's phonotactics: %s (at position %d)",
+					validationErr.Message,
+					validationErr.Position,
+				)
+			case errors.Is(err, sql.ErrNoRows):
+				form.Errors[""] = "Language has no phonology to validate against"
+			default:
+				form.Errors[""] = fmt.Sprintf("Failed to validate word: %s", err)
+			}
+		}
+	}
+	if !form.valid() {
+		cfg.renderEditWords(w, r, language, form, http.StatusUnprocessableEntity)
+		return
+	}
+
+	var word database.Word
+	err := cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		var err error
+		word, err = tx.CreateWord(r.Context(), database.CreateWordParams{
+			Word:       form.Value("word"),
+			LanguageID: language.ID,
+		})
+		if err != nil {
+			return stepError("create word", err, getFailedCreationCode(err))
+		}
+
+		if formatted := form.Value("font_formatted"); formatted != "" {
+			word, err = tx.UpdateWord(r.Context(), database.UpdateWordParams{
+				SetFormatted: true,
+				Formatted:    formatted,
+				ID:           word.ID,
+			})
+			if err != nil {
+				return stepError("update word", err, http.StatusInternalServerError)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if getFailedCreationCode(err) == http.StatusUnprocessableEntity {
+			form.Errors["word"] = fmt.Sprintf("%s already has this word", language.Name)
+		} else {
+			form.Errors[""] = fmt.Sprintf("Failed to create word: %s", err)
+		}
+		cfg.renderEditWords(w, r, language, form, getFailedCreationCode(err))
+		return
+	}
+
+	http.Redirect(w, r, editorWordPath(language, word), http.StatusSeeOther)
+}
+
+// A definition of the word being edited, with its form.
+type editorDefinition struct {
+	ID   uuid.UUID
+	Form editorForm
+}
+
+// The word editing page. Each form starts out holding the stored values.
+type editWordPage struct {
+	CSRFToken   string
+	Language    Language
+	Word        Word
+	WordForm    editorForm
+	Definitions []editorDefinition
+	AddForm     editorForm
+}
+
+// Renders the page for editing a word. failed, if given, swaps in the form of
+// a failed submission, so that it shows what was submitted and what was wrong
+// with it.
+func (cfg *apiConfig) renderEditWord(
+	w http.ResponseWriter,
+	r *http.Request,
+	language database.Language,
+	word database.Word,
+	status int,
+	failed func(*editWordPage),
+) {
+	definitions, err := cfg.store.GetDefinitionsOfWord(r.Context(), word.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve definitions", http.StatusInternalServerError)
+		return
+	}
+
+	marshallable := getMarshallableWord(word, definitions)
+	page := editWordPage{
+		CSRFToken: auth.SessionFromContext(r.Context()).CSRFToken,
+		Language:  getMarshallableLanguage(language),
+		Word:      marshallable,
+		WordForm: newEditorForm(map[string]string{
+			"word":           marshallable.Word,
+			"font_formatted": marshallable.FontFormatted,
+		}),
+		AddForm: newEditorForm(nil),
+	}
+	for _, definition := range definitions {
+		page.Definitions = append(page.Definitions, editorDefinition{
+			ID: definition.ID,
+			Form: newEditorForm(map[string]string{
+				"content":        definition.Content,
+				"part_of_speech": definition.PartOfSpeech,
+			}),
+		})
+	}
+	if failed != nil {
+		failed(&page)
+	}
+
+	renderEditorPage(w, "edit-word", status, page)
+}
+
+// Show the word in the path parameters, with forms for it and each of its
+// definitions.
+func (cfg *apiConfig) editWord(w http.ResponseWriter, r *http.Request) {
+	language, word, ok := cfg.getEditorWord(w, r)
+	if !ok {
+		return
+	}
+	cfg.renderEditWord(w, r, language, word, http.StatusOK, nil)
+}
+
+// Save the word form. Unlike the API, an empty font-formatted form clears it,
+// as the form always holds the whole value.
+func (cfg *apiConfig) editorUpdateWord(w http.ResponseWriter, r *http.Request) {
+	language, word, ok := cfg.getEditorWord(w, r)
+	if !ok {
+		return
+	}
+
+	form := readEditorForm(r, "word", "font_formatted")
+	form.require(map[string]string{"word": "Word"})
+	if form.valid() {
+		updated, err := cfg.store.UpdateWord(r.Context(), database.UpdateWordParams{
+			SetWord:      true,
+			Word:         form.Value("word"),
+			SetFormatted: true,
+			Formatted:    form.Value("font_formatted"),
+			ID:           word.ID,
+		})
+		if err == nil {
+			http.Redirect(w, r, editorWordPath(language, updated), http.StatusSeeOther)
+			return
+		}
+		if getFailedCreationCode(err) == http.StatusUnprocessableEntity {
+			form.Errors["word"] = fmt.Sprintf("%s already has this word", language.Name)
+		} else {
+			form.Errors[""] = fmt.Sprintf("Failed to update word: %s", err)
+		}
+	}
+
+	cfg.renderEditWord(w, r, language, word, http.StatusUnprocessableEntity, func(page *editWordPage) {
+		page.WordForm = form
+	})
+}
+
+// Reads a definition form, and gives the reason the API would refuse it.
+func readDefinitionForm(r *http.Request) editorForm {
+	form := readEditorForm(r, "content", "part_of_speech")
+	form.require(map[string]string{"content": "Definition", "part_of_speech": "Part of speech"})
+	return form
+}
+
+// Explains a failed definition write on its form.
+func (f editorForm) definitionError(err error) int {
+	status := getFailedCreationCode(err)
+	if status == http.StatusUnprocessableEntity {
+		f.Errors["content"] = "This word already has this definition"
+	} else {
+		f.Errors[""] = fmt.Sprintf("Failed to save definition: %s", err)
+	}
+	return status
+}
+
+// Add a definition to the word in the path parameters.
+func (cfg *apiConfig) editorCreateDefinition(w http.ResponseWriter, r *http.Request) {
+	language, word, ok := cfg.getEditorWord(w, r)
+	if !ok {
+		return
+	}
+
+	form := readDefinitionForm(r)
+	status := http.StatusUnprocessableEntity
+	if form.valid() {
+		_, err := cfg.store.CreateDefinition(r.Context(), database.CreateDefinitionParams{
+			WordID:       word.ID,
+			Content:      form.Value("content"),
+			PartOfSpeech: form.Value("part_of_speech"),
+		})
+		if err == nil {
+			http.Redirect(w, r, editorWordPath(language, word), http.StatusSeeOther)
+			return
+		}
+		status = form.definitionError(err)
+	}
+
+	cfg.renderEditWord(w, r, language, word, status, func(page *editWordPage) {
+		page.AddForm = form
+	})
+}
+
+// Finds the definition in the path parameters, responding if it is not one
+// of word's.
+func (cfg *apiConfig) getEditorDefinition(w http.ResponseWriter, r *http.Request, word database.Word) (database.Definition, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid definition ID", http.StatusBadRequest)
+		return database.Definition{}, false
+	}
+
+	definition, err := cfg.store.GetDefinitionByID(r.Context(), id)
+	if err != nil || definition.WordID != word.ID {
+		http.Error(w, "Definition not found", http.StatusNotFound)
+		return database.Definition{}, false
+	}
+	return definition, true
+}
+
+// Save the form of one of the word's definitions.
+func (cfg *apiConfig) editorUpdateDefinition(w http.ResponseWriter, r *http.Request) {
+	language, word, ok := cfg.getEditorWord(w, r)
+	if !ok {
+		return
+	}
+	definition, ok := cfg.getEditorDefinition(w, r, word)
+	if !ok {
+		return
+	}
+
+	form := readDefinitionForm(r)
+	status := http.StatusUnprocessableEntity
+	if form.valid() {
+		_, err := cfg.store.UpdateDefinition(r.Context(), database.UpdateDefinitionParams{
+			Content:      form.Value("content"),
+			PartOfSpeech: form.Value("part_of_speech"),
+			ID:           definition.ID,
+		})
+		if err == nil {
+			http.Redirect(w, r, editorWordPath(language, word), http.StatusSeeOther)
+			return
+		}
+		status = form.definitionError(err)
+	}
+
+	cfg.renderEditWord(w, r, language, word, status, func(page *editWordPage) {
+		for i := range page.Definitions {
+			if page.Definitions[i].ID == definition.ID {
+				page.Definitions[i].Form = form
+			}
+		}
+	})
+}
+
+// Delete one of the word's definitions.
+func (cfg *apiConfig) editorDeleteDefinition(w http.ResponseWriter, r *http.Request) {
+	language, word, ok := cfg.getEditorWord(w, r)
+	if !ok {
+		return
+	}
+	definition, ok := cfg.getEditorDefinition(w, r, word)
+	if !ok {
+		return
+	}
+
+	if err := cfg.store.DeleteDefinition(r.Context(), definition.ID); err != nil {
+		http.Error(w, "Failed to delete definition", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, editorWordPath(language, word), http.StatusSeeOther)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"vastestsea/internal/auth"
+)
+
+// Serves the test server to a client that keeps cookies, as a browser would,
+// and doesn't follow redirects.
+func newTestEditor(t *testing.T) (*httptest.Server, *http.Client) {
+	t.Helper()
+	server := newTestServer(t)
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := server.Client()
+	client.Jar = jar
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return server, client
+}
+
+var csrfInput = regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+
+// Loads the login form, returning its CSRF token.
+func getLoginToken(t *testing.T, server *httptest.Server, client *http.Client) string {
+	t.Helper()
+	res, err := client.Get(server.URL + "/edit/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	match := csrfInput.FindSubmatch(body)
+	if match == nil {
+		t.Fatalf("no CSRF token in the login form:\n%s", body)
+	}
+	return string(match[1])
+}
+
+func postLogin(t *testing.T, server *httptest.Server, client *http.Client, token, password string) int {
+	t.Helper()
+	res, err := client.PostForm(server.URL+"/edit/login", url.Values{
+		"csrf_token": {token},
+		"password":   {password},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	return res.StatusCode
+}
+
+func hasSessionCookie(t *testing.T, server *httptest.Server, client *http.Client) bool {
+	t.Helper()
+	u, err := url.Parse(server.URL + "/edit/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cookie := range client.Jar.Cookies(u) {
+		if cookie.Name == auth.SessionCookie {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEditorLogin(t *testing.T) {
+	server, client := newTestEditor(t)
+
+	token := getLoginToken(t, server, client)
+	if hasSessionCookie(t, server, client) {
+		t.Fatal("visiting the login page started a session")
+	}
+
+	if status := postLogin(t, server, client, token+"x", testEditorPassword); status != http.StatusForbidden {
+		t.Fatalf("got status %d for a forged token, want %d", status, http.StatusForbidden)
+	}
+	if status := postLogin(t, server, client, token, "wrong"); status != http.StatusUnauthorized {
+		t.Fatalf("got status %d for a wrong password, want %d", status, http.StatusUnauthorized)
+	}
+	if hasSessionCookie(t, server, client) {
+		t.Fatal("a failed login started a session")
+	}
+
+	if status := postLogin(t, server, client, token, testEditorPassword); status != http.StatusSeeOther {
+		t.Fatalf("got status %d for a login, want %d", status, http.StatusSeeOther)
+	}
+	if !hasSessionCookie(t, server, client) {
+		t.Fatal("logging in did not start a session")
+	}
+
+	res, err := client.Get(server.URL + "/edit/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d in the editor, want %d", res.StatusCode, http.StatusOK)
+	}
+}
+
+func TestFailedLoginsAreThrottled(t *testing.T) {
+	server, client := newTestEditor(t)
+	token := getLoginToken(t, server, client)
+
+	for i := 0; i < 5; i++ {
+		if status := postLogin(t, server, client, token, "wrong"); status != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: got status %d, want %d", i+1, status, http.StatusUnauthorized)
+		}
+	}
+	// Even the right password is refused until the window has passed.
+	if status := postLogin(t, server, client, token, testEditorPassword); status != http.StatusTooManyRequests {
+		t.Fatalf("got status %d after five failures, want %d", status, http.StatusTooManyRequests)
+	}
+}
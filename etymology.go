@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Etymology Handlers
+ */
+
+// The kinds of etymological relation a word can have to its source, as
+// allowed by the word_relations schema.
+var wordRelationTypes = []string{"inherited", "derived", "borrowed", "compound"}
+
+// Record that the word in the path parameters comes from another word, which
+// may belong to any language. Edges that would make a word its own ancestor
+// are rejected.
+func (cfg *apiConfig) createWordRelation(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	type reqParams struct {
+		Source struct {
+			Language string `json:"language"`
+			Word     string `json:"word"`
+		} `json:"source"`
+		RelationType string `json:"relation_type"`
+	}
+
+	params := reqParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if params.Source.Language == "" || params.Source.Word == "" || params.RelationType == "" {
+		respondError("Invalid request body", w, http.StatusBadRequest)
+		return
+	}
+
+	if !slices.Contains(wordRelationTypes, params.RelationType) {
+		respondError(
+			fmt.Sprintf("relation_type must be one of %s", strings.Join(wordRelationTypes, ", ")),
+			w,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	sourceLanguage, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(params.Source.Language))
+	if err != nil {
+		respondError("Source language not found", w, http.StatusNotFound)
+		return
+	}
+	source, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+		Word:       strings.ToLower(params.Source.Word),
+		LanguageID: sourceLanguage.ID,
+	})
+	if err != nil {
+		respondError("Source word not found", w, http.StatusNotFound)
+		return
+	}
+
+	// Under READ COMMITTED, two transactions could each pass the cycle check
+	// before either commits, and close a cycle between them. Every insert
+	// takes the same lock before checking, so they check one at a time, each
+	// seeing the edges of those before it.
+	var relation database.WordRelation
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		if source.ID == word.ID {
+			return stepError("create relation", errors.New("a word cannot derive from itself"), http.StatusUnprocessableEntity)
+		}
+
+		if err := tx.LockWordRelations(r.Context()); err != nil {
+			return stepError("lock relations", err, http.StatusInternalServerError)
+		}
+
+		ancestors, err := tx.GetAncestorRelations(r.Context(), source.ID)
+		if err != nil {
+			return stepError("check for cycles", err, http.StatusInternalServerError)
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.SourceWordID == word.ID {
+				return stepError(
+					"create relation",
+					fmt.Errorf("%s already descends from %s", source.Word, word.Word),
+					http.StatusUnprocessableEntity,
+				)
+			}
+		}
+
+		relation, err = tx.CreateWordRelation(r.Context(), database.CreateWordRelationParams{
+			WordID:       word.ID,
+			SourceWordID: source.ID,
+			RelationType: params.RelationType,
+		})
+		if err != nil {
+			return stepError("create relation", err, getFailedCreationCode(err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	writeResponse(getMarshallableWordRelation(relation), w, http.StatusCreated)
+}
+
+// Remove an etymological relation from the word in the path parameters.
+func (cfg *apiConfig) deleteWordRelation(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError("Invalid relation ID", w, http.StatusBadRequest)
+		return
+	}
+
+	relation, err := cfg.store.GetWordRelationByID(r.Context(), id)
+	if err != nil || relation.WordID != word.ID {
+		respondError("Relation not found", w, http.StatusNotFound)
+		return
+	}
+
+	if err := cfg.store.DeleteWordRelation(r.Context(), relation.ID); err != nil {
+		respondError("Failed to delete relation", w, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Get the full ancestor tree of the word in the path parameters.
+func (cfg *apiConfig) getEtymology(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	relations, err := cfg.store.GetAncestorRelations(r.Context(), word.ID)
+	if err != nil {
+		respondError("Failed to retrieve etymology", w, http.StatusInternalServerError)
+		return
+	}
+
+	tree, err := cfg.buildEtymologyTree(r, word, relations, true)
+	if err != nil {
+		respondError("Failed to retrieve etymology", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(tree, w, http.StatusOK)
+}
+
+// Get the full tree of reflexes of the word in the path parameters.
+func (cfg *apiConfig) getDescendants(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	relations, err := cfg.store.GetDescendantRelations(r.Context(), word.ID)
+	if err != nil {
+		respondError("Failed to retrieve descendants", w, http.StatusInternalServerError)
+		return
+	}
+
+	tree, err := cfg.buildEtymologyTree(r, word, relations, false)
+	if err != nil {
+		respondError("Failed to retrieve descendants", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(tree, w, http.StatusOK)
+}
+
+// Arranges the edges returned by GetAncestorRelations or
+// GetDescendantRelations into a tree rooted at root. A word reachable along
+// several paths is expanded only where it first appears; elsewhere it is
+// marked as repeated and left without branches, so the tree grows with the
+// number of edges rather than the number of paths.
+func (cfg *apiConfig) buildEtymologyTree(
+	r *http.Request,
+	root database.Word,
+	relations []database.WordRelation,
+	ancestors bool,
+) (EtymologyNode, error) {
+	wordIDs := []uuid.UUID{}
+	children := map[uuid.UUID][]database.WordRelation{}
+	for _, rel := range relations {
+		if ancestors {
+			children[rel.WordID] = append(children[rel.WordID], rel)
+			wordIDs = append(wordIDs, rel.SourceWordID)
+		} else {
+			children[rel.SourceWordID] = append(children[rel.SourceWordID], rel)
+			wordIDs = append(wordIDs, rel.WordID)
+		}
+	}
+
+	words, err := cfg.store.GetWordsByIDs(r.Context(), wordIDs)
+	if err != nil {
+		return EtymologyNode{}, err
+	}
+	wordsByID := map[uuid.UUID]database.Word{root.ID: root}
+	for _, word := range words {
+		wordsByID[word.ID] = word
+	}
+
+	// Expanding each word once also guards against cycles, which inserts
+	// should already prevent.
+	expanded := map[uuid.UUID]bool{}
+	var build func(word database.Word, via *database.WordRelation) EtymologyNode
+	build = func(word database.Word, via *database.WordRelation) EtymologyNode {
+		node := EtymologyNode{
+			Word: getMarshallableWord(word, []database.Definition{}),
+		}
+		if via != nil {
+			relation := getMarshallableWordRelation(*via)
+			node.Relation = &relation
+		}
+
+		if expanded[word.ID] {
+			node.Repeated = true
+			return node
+		}
+		expanded[word.ID] = true
+
+		for _, rel := range children[word.ID] {
+			next := rel.WordID
+			if ancestors {
+				next = rel.SourceWordID
+			}
+
+			child := build(wordsByID[next], &rel)
+			if ancestors {
+				node.Sources = append(node.Sources, child)
+			} else {
+				node.Descendants = append(node.Descendants, child)
+			}
+		}
+
+		return node
+	}
+
+	return build(root, nil), nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSharedAncestorsAreExpandedOnce(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	for _, word := range []string{"root", "left", "right", "leaf"} {
+		mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": word}, nil, http.StatusCreated)
+	}
+
+	derive := func(word, source string) {
+		t.Helper()
+		body := map[string]any{
+			"source":        map[string]string{"language": "quenya", "word": source},
+			"relation_type": "derived",
+		}
+		mustCall(t, server, "POST", "/vs/languages/quenya/words/"+word+"/etymology", body, nil, http.StatusCreated)
+	}
+	derive("left", "root")
+	derive("right", "root")
+	derive("leaf", "left")
+	derive("leaf", "right")
+
+	// Closing a cycle is refused.
+	body := map[string]any{
+		"source":        map[string]string{"language": "quenya", "word": "leaf"},
+		"relation_type": "derived",
+	}
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/root/etymology", body, nil, http.StatusUnprocessableEntity)
+
+	tree := EtymologyNode{}
+	mustCall(t, server, "GET", "/vs/languages/quenya/words/leaf/etymology", nil, &tree, http.StatusOK)
+	if len(tree.Sources) != 2 {
+		t.Fatalf("got %d sources of leaf, want 2", len(tree.Sources))
+	}
+
+	expanded, repeated := 0, 0
+	for _, parent := range tree.Sources {
+		if len(parent.Sources) != 1 || parent.Sources[0].Word.Word != "root" {
+			t.Fatalf("%s: want root as its only source", parent.Word.Word)
+		}
+		if parent.Sources[0].Repeated {
+			repeated++
+		} else {
+			expanded++
+		}
+	}
+	if expanded != 1 || repeated != 1 {
+		t.Fatalf("root expanded %d times and repeated %d times, want once each", expanded, repeated)
+	}
+}
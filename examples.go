@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/interlinear"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Example Sentence Handlers
+ */
+
+type exampleParams struct {
+	DefinitionID *uuid.UUID `json:"definition_id"`
+	Source       string     `json:"source"`
+	Gloss        string     `json:"gloss"`
+	Translation  string     `json:"translation"`
+}
+
+type responseAlignmentError struct {
+	Error string `json:"error"`
+	Word  int    `json:"word_index"`
+}
+
+// Checks an example's fields, and that its source and gloss align. Writes the
+// appropriate error response and returns false on failure.
+func validateExample(params exampleParams, w http.ResponseWriter) bool {
+	if params.Source == "" || params.Gloss == "" || params.Translation == "" {
+		respondError("Invalid request body: source, gloss and translation are required", w, http.StatusBadRequest)
+		return false
+	}
+
+	if _, err := interlinear.Align(params.Source, params.Gloss); err != nil {
+		var alignErr *interlinear.AlignmentError
+		if errors.As(err, &alignErr) {
+			writeResponse(responseAlignmentError{
+				Error: fmt.Sprintf("Source and gloss do not align: %s", alignErr),
+				Word:  alignErr.Word,
+			}, w, http.StatusUnprocessableEntity)
+			return false
+		}
+		respondError(fmt.Sprintf("Could not align example: %s", err), w, http.StatusInternalServerError)
+		return false
+	}
+
+	return true
+}
+
+// Resolves the definition an example is to be attached to, which must be a
+// sense of the given word. Writes a not found response and returns false on
+// failure.
+func (cfg *apiConfig) getExampleDefinition(
+	w http.ResponseWriter,
+	r *http.Request,
+	word database.Word,
+	id *uuid.UUID,
+) (uuid.NullUUID, bool) {
+	if id == nil {
+		return uuid.NullUUID{}, true
+	}
+
+	definition, err := cfg.store.GetDefinitionByID(r.Context(), *id)
+	if err != nil || definition.WordID != word.ID {
+		respondError("Definition not found", w, http.StatusNotFound)
+		return uuid.NullUUID{}, false
+	}
+
+	return uuid.NullUUID{UUID: definition.ID, Valid: true}, true
+}
+
+// Resolves the example identified by the `id` path parameter, ensuring it
+// belongs to a word of the language in the `language` path parameter. Writes
+// the appropriate error response and returns false on failure.
+func (cfg *apiConfig) getExampleFromPath(w http.ResponseWriter, r *http.Request) (database.Example, database.Word, bool) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return database.Example{}, database.Word{}, false
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError("Invalid example ID", w, http.StatusBadRequest)
+		return database.Example{}, database.Word{}, false
+	}
+
+	example, err := cfg.store.GetExampleByID(r.Context(), id)
+	if err != nil {
+		respondError("Example not found", w, http.StatusNotFound)
+		return database.Example{}, database.Word{}, false
+	}
+
+	word, err := cfg.store.GetWordByID(r.Context(), example.WordID)
+	if err != nil || word.LanguageID != language.ID {
+		respondError("Example not found", w, http.StatusNotFound)
+		return database.Example{}, database.Word{}, false
+	}
+
+	return example, word, true
+}
+
+// Get the example sentences of the language in the path parameter. The
+// `word` query parameter limits them to the examples of a single word.
+func (cfg *apiConfig) getExamples(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	var examples []database.Example
+	if wordName := r.URL.Query().Get("word"); wordName != "" {
+		word, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+			Word:       strings.ToLower(wordName),
+			LanguageID: language.ID,
+		})
+		if err != nil {
+			respondError("Word not found", w, http.StatusNotFound)
+			return
+		}
+		examples, err = cfg.store.GetExamplesOfWord(r.Context(), word.ID)
+	} else {
+		examples, err = cfg.store.GetExamplesOfLanguage(r.Context(), language.ID)
+	}
+	if err != nil {
+		respondError("Failed to retrieve examples", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(getMarshallableExamples(examples), w, http.StatusOK)
+}
+
+// Get a single example sentence.
+func (cfg *apiConfig) getExample(w http.ResponseWriter, r *http.Request) {
+	example, _, ok := cfg.getExampleFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	writeResponse(getMarshallableExample(example), w, http.StatusOK)
+}
+
+// Create an example sentence for a word of the language in the path
+// parameter. The word is given in the body, along with the definition it
+// illustrates, if any.
+func (cfg *apiConfig) createExample(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	type reqParams struct {
+		Word string `json:"word"`
+		exampleParams
+	}
+
+	params := reqParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if params.Word == "" {
+		respondError("Invalid request body: word is required", w, http.StatusBadRequest)
+		return
+	}
+	if !validateExample(params.exampleParams, w) {
+		return
+	}
+
+	word, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+		Word:       strings.ToLower(params.Word),
+		LanguageID: language.ID,
+	})
+	if err != nil {
+		respondError("Word not found", w, http.StatusNotFound)
+		return
+	}
+
+	definitionID, ok := cfg.getExampleDefinition(w, r, word, params.DefinitionID)
+	if !ok {
+		return
+	}
+
+	example, err := cfg.store.CreateExample(r.Context(), database.CreateExampleParams{
+		WordID:       word.ID,
+		DefinitionID: definitionID,
+		Source:       params.Source,
+		Gloss:        params.Gloss,
+		Translation:  params.Translation,
+	})
+	if err != nil {
+		respondError(fmt.Sprintf("Failed to create example: %s", err), w, getFailedCreationCode(err))
+		return
+	}
+
+	writeResponse(getMarshallableExample(example), w, http.StatusCreated)
+}
+
+// Replace the text, gloss, translation and definition of an example.
+func (cfg *apiConfig) updateExample(w http.ResponseWriter, r *http.Request) {
+	example, word, ok := cfg.getExampleFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := exampleParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if !validateExample(params, w) {
+		return
+	}
+
+	definitionID, ok := cfg.getExampleDefinition(w, r, word, params.DefinitionID)
+	if !ok {
+		return
+	}
+
+	example, err := cfg.store.UpdateExample(r.Context(), database.UpdateExampleParams{
+		ID:           example.ID,
+		DefinitionID: definitionID,
+		Source:       params.Source,
+		Gloss:        params.Gloss,
+		Translation:  params.Translation,
+	})
+	if err != nil {
+		respondError(fmt.Sprintf("Failed to update example: %s", err), w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(getMarshallableExample(example), w, http.StatusOK)
+}
+
+// Delete an example sentence.
+func (cfg *apiConfig) deleteExample(w http.ResponseWriter, r *http.Request) {
+	example, _, ok := cfg.getExampleFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := cfg.store.DeleteExample(r.Context(), example.ID); err != nil {
+		respondError(fmt.Sprintf("Could not delete example: %s", err), w, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
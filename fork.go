@@ -0,0 +1,186 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Fork Handlers
+ */
+
+type rewriteRule struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Applies each rewrite rule in order, replacing every occurrence.
+func rewriteHeadword(word string, rules []rewriteRule) string {
+	for _, rule := range rules {
+		word = strings.ReplaceAll(word, rule.From, rule.To)
+	}
+	return word
+}
+
+// Fork the language in the path parameter into a new daughter language or
+// dialect. Every word and definition is copied, each headword is optionally
+// rewritten by the ordered `rules`, and every copy records the word it was
+// inherited from in its etymology. With `dry_run`, the fork is only
+// previewed.
+func (cfg *apiConfig) forkLanguage(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	parent, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	type reqParams struct {
+		Name   string        `json:"name"`
+		Rules  []rewriteRule `json:"rules"`
+		DryRun bool          `json:"dry_run"`
+	}
+
+	params := reqParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if params.Name == "" {
+		respondError("Invalid request body", w, http.StatusBadRequest)
+		return
+	}
+	for _, rule := range params.Rules {
+		if rule.From == "" {
+			respondError("Rewrite rules must have a non-empty `from`", w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Checked up front so that a dry run reports it too. The insert below
+	// still catches a language created in the meantime.
+	_, err = cfg.store.GetLanguage(r.Context(), strings.ToLower(params.Name))
+	if err == nil {
+		respondError(fmt.Sprintf("Language %q already exists", params.Name), w, http.StatusUnprocessableEntity)
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		respondError("Failed to retrieve languages", w, http.StatusInternalServerError)
+		return
+	}
+
+	words, err := cfg.store.GetWordsByLanguageID(r.Context(), parent.ID)
+	if err != nil {
+		respondError("Failed to retrieve words", w, http.StatusInternalServerError)
+		return
+	}
+
+	wordIDs := []uuid.UUID{}
+	for _, word := range words {
+		wordIDs = append(wordIDs, word.ID)
+	}
+	definitions, err := cfg.store.GetDefinitionsOfWords(r.Context(), wordIDs)
+	if err != nil {
+		respondError("Failed to retrieve definitions", w, http.StatusInternalServerError)
+		return
+	}
+	definitionsByWord := map[uuid.UUID][]database.Definition{}
+	for _, definition := range definitions {
+		definitionsByWord[definition.WordID] = append(definitionsByWord[definition.WordID], definition)
+	}
+
+	// Rewrites can merge distinct words, which the fork could not store.
+	fork := Fork{
+		DryRun: params.DryRun,
+		Name:   params.Name,
+		Words:  []ForkedWord{},
+	}
+	sources := map[string]string{}
+	for _, word := range words {
+		rewritten := rewriteHeadword(word.Word, params.Rules)
+		if rewritten == "" {
+			respondError(fmt.Sprintf("Rewriting %q leaves an empty word", word.Word), w, http.StatusUnprocessableEntity)
+			return
+		}
+		if other, ok := sources[rewritten]; ok {
+			respondError(
+				fmt.Sprintf("Rewriting merges %q and %q into %q", other, word.Word, rewritten),
+				w,
+				http.StatusUnprocessableEntity,
+			)
+			return
+		}
+		sources[rewritten] = word.Word
+
+		fork.Words = append(fork.Words, ForkedWord{
+			SourceWordID: word.ID,
+			Source:       word.Word,
+			Word:         rewritten,
+			Definitions:  len(definitionsByWord[word.ID]),
+		})
+	}
+
+	if params.DryRun {
+		writeResponse(fork, w, http.StatusOK)
+		return
+	}
+
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		language, err := tx.CreateLanguage(r.Context(), params.Name)
+		if err != nil {
+			return stepError("create language", err, getFailedCreationCode(err))
+		}
+		marshallable := getMarshallableLanguage(language)
+		fork.Language = &marshallable
+
+		for i, word := range words {
+			copied, err := tx.CreateFormattedWord(r.Context(), database.CreateFormattedWordParams{
+				Word:          fork.Words[i].Word,
+				FontFormatted: word.FontFormatted,
+				LanguageID:    language.ID,
+			})
+			if err != nil {
+				return stepError(fmt.Sprintf("copy word %q", word.Word), err, getFailedCreationCode(err))
+			}
+			fork.Words[i].WordID = &copied.ID
+
+			for _, definition := range definitionsByWord[word.ID] {
+				_, err := tx.CreateDefinition(r.Context(), database.CreateDefinitionParams{
+					WordID:       copied.ID,
+					Content:      definition.Content,
+					PartOfSpeech: definition.PartOfSpeech,
+				})
+				if err != nil {
+					return stepError(fmt.Sprintf("copy definitions of %q", word.Word), err, http.StatusInternalServerError)
+				}
+			}
+
+			_, err = tx.CreateWordRelation(r.Context(), database.CreateWordRelationParams{
+				WordID:       copied.ID,
+				SourceWordID: word.ID,
+				RelationType: "inherited",
+			})
+			if err != nil {
+				return stepError(fmt.Sprintf("link %q to its source", word.Word), err, http.StatusInternalServerError)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	writeResponse(fork, w, http.StatusCreated)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestForkRefusesATakenName(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "sindarin"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+
+	for _, dryRun := range []bool{true, false} {
+		body := map[string]any{"name": "Sindarin", "dry_run": dryRun}
+		mustCall(t, server, "POST", "/vs/languages/quenya/fork", body, nil, http.StatusUnprocessableEntity)
+	}
+
+	preview := Fork{}
+	body := map[string]any{"name": "telerin", "dry_run": true}
+	mustCall(t, server, "POST", "/vs/languages/quenya/fork", body, &preview, http.StatusOK)
+	if len(preview.Words) != 1 {
+		t.Fatalf("got %d words in the preview, want 1", len(preview.Words))
+	}
+	mustCall(t, server, "GET", "/vs/languages/telerin", nil, nil, http.StatusNotFound)
+}
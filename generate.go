@@ -0,0 +1,119 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/phonology"
+)
+
+/*
+ * Word Generation Handlers
+ */
+
+const (
+	defaultGenerateCount = 20
+	maxGenerateCount     = 500
+	maxGenerateSyllables = 12
+)
+
+type GeneratedWords struct {
+	Seed  int64    `json:"seed"`
+	Words []string `json:"words"`
+}
+
+// Generate candidate words for the language in the path parameter. The
+// phonemes, weighted syllable templates and banned clusters are taken from
+// the request body, falling back on the language's phonology for anything
+// left out. Words already in the language are never suggested, and the same
+// seed always gives the same words. Nothing is written.
+func (cfg *apiConfig) generateWords(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	type reqParams struct {
+		Phonemes       []phonology.Phoneme          `json:"phonemes"`
+		Categories     map[string][]string          `json:"categories"`
+		Templates      []phonology.WeightedTemplate `json:"templates"`
+		BannedClusters []string                     `json:"banned_clusters"`
+		Count          int                          `json:"count"`
+		MinSyllables   int                          `json:"min_syllables"`
+		MaxSyllables   int                          `json:"max_syllables"`
+		Seed           int64                        `json:"seed"`
+	}
+
+	params := reqParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if params.Count == 0 {
+		params.Count = defaultGenerateCount
+	}
+	if params.Count < 1 || params.Count > maxGenerateCount {
+		respondError(fmt.Sprintf("count must be between 1 and %d", maxGenerateCount), w, http.StatusBadRequest)
+		return
+	}
+	if params.MinSyllables == 0 {
+		params.MinSyllables = 1
+	}
+	if params.MaxSyllables == 0 {
+		params.MaxSyllables = max(params.MinSyllables, 3)
+	}
+	if params.MaxSyllables > maxGenerateSyllables {
+		respondError(fmt.Sprintf("max_syllables must be at most %d", maxGenerateSyllables), w, http.StatusBadRequest)
+		return
+	}
+
+	inventory, err := getInventory(r.Context(), cfg.store, language.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondError("Failed to retrieve phonology", w, http.StatusInternalServerError)
+		return
+	}
+	if params.Phonemes != nil {
+		inventory.Phonemes = params.Phonemes
+	}
+	if params.Categories != nil {
+		inventory.Categories = params.Categories
+	}
+	if params.BannedClusters != nil {
+		inventory.ForbiddenClusters = params.BannedClusters
+	}
+
+	generator, err := phonology.NewGenerator(
+		inventory,
+		params.Templates,
+		params.MinSyllables,
+		params.MaxSyllables,
+	)
+	if err != nil {
+		respondError(fmt.Sprintf("Invalid generator settings: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	words, err := cfg.store.GetWordsByLanguageID(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve words", w, http.StatusInternalServerError)
+		return
+	}
+
+	existing := map[string]bool{}
+	for _, word := range words {
+		existing[strings.ToLower(word.Word)] = true
+	}
+
+	generated := generator.Generate(params.Count, params.Seed, func(word string) bool {
+		return existing[word]
+	})
+
+	writeResponse(GeneratedWords{Seed: params.Seed, Words: generated}, w, http.StatusOK)
+}
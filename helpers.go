@@ -2,9 +2,16 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"vastestsea/internal/store"
+
+	"github.com/lib/pq"
 )
 
 type responseSuccess struct {
@@ -50,17 +57,124 @@ func writeResponse[T any](res T, w http.ResponseWriter, status int) {
 	w.Write(data)
 }
 
+// Decodes a JSON body holding either a single object or an array of them.
+func decodeOneOrMany[T any](body io.Reader) ([]T, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		items := []T{}
+		err := json.Unmarshal(raw, &items)
+		return items, err
+	}
+	var single T
+	if err := json.Unmarshal(raw, &single); err != nil {
+		return nil, err
+	}
+	return []T{single}, nil
+}
+
+// Whether the client asked for HTML rather than JSON, either with
+// `?format=html` or by preferring text/html to application/json in its Accept
+// header.
+func wantsHTML(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "html"
+	}
+	accept := r.Header.Get("Accept")
+	return acceptQuality(accept, "text/html") > acceptQuality(accept, "application/json")
+}
+
+// The quality an Accept header gives a media type, taken from the most
+// specific range that matches it, or 0 if none does.
+func acceptQuality(accept, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	quality, specificity := 0.0, 0
+	for _, accepted := range strings.Split(accept, ",") {
+		params := strings.Split(accepted, ";")
+		var s int
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case mediaType:
+			s = 3
+		case mainType + "/*":
+			s = 2
+		case "*/*":
+			s = 1
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(name) != "q" {
+				continue
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = f
+			}
+		}
+		quality, specificity = q, s
+	}
+	return quality
+}
+
 // Returns the correct status code, depending on if the failed creation
 // was due to a unique constraint violation, or some other unanticipated
 // issue.
 func getFailedCreationCode(err error) int {
-	if strings.HasPrefix(err.Error(), "pq: duplicate key value") {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, store.ErrDuplicateKey) {
 		return http.StatusUnprocessableEntity
 	}
 
 	return http.StatusInternalServerError
 }
 
+// Describes which step of a transactional, multi-step write failed, along
+// with the status code that failure should be reported with.
+type txStepError struct {
+	step   string
+	status int
+	err    error
+}
+
+func (e *txStepError) Error() string {
+	return fmt.Sprintf("failed to %s: %s", e.step, e.err)
+}
+
+func (e *txStepError) Unwrap() error {
+	return e.err
+}
+
+func stepError(step string, err error, status int) error {
+	return &txStepError{step: step, status: status, err: err}
+}
+
+// Responds to a failed transaction. Errors that name their failed step keep
+// their own status code; anything else, such as a failed commit, is a 500.
+func respondTxError(err error, w http.ResponseWriter) {
+	var stepErr *txStepError
+	if errors.As(err, &stepErr) {
+		respondError(
+			fmt.Sprintf("Transaction rolled back, %s", stepErr),
+			w,
+			stepErr.status,
+		)
+		return
+	}
+
+	respondError(fmt.Sprintf("Transaction failed: %s", err), w, http.StatusInternalServerError)
+}
+
 // Constructs an authenticated endpoint
 func (cfg *apiConfig) getAuthenticatedHandler(
 	handlerFunc func(w http.ResponseWriter, r *http.Request),
@@ -69,3 +183,14 @@ func (cfg *apiConfig) getAuthenticatedHandler(
 		http.HandlerFunc(handlerFunc),
 	)
 }
+
+// Constructs an endpoint of the web editor, which needs a logged in session
+// instead of an API key.
+func (cfg *apiConfig) getEditorHandler(
+	handlerFunc func(w http.ResponseWriter, r *http.Request),
+) http.Handler {
+	return cfg.auth.AuthenticateSession(
+		editorLoginPath,
+		http.HandlerFunc(handlerFunc),
+	)
+}
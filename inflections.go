@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/inflection"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Inflection Class Handlers
+ */
+
+type inflectionClassParams struct {
+	PartOfSpeech string            `json:"part_of_speech"`
+	Name         string            `json:"name"`
+	Rules        []inflection.Rule `json:"rules"`
+}
+
+func (p inflectionClassParams) validate() error {
+	if p.PartOfSpeech == "" || p.Name == "" {
+		return fmt.Errorf("part_of_speech and name are required")
+	}
+	for i, rule := range p.Rules {
+		if rule.Row == "" {
+			return fmt.Errorf("rule %d has no row", i)
+		}
+	}
+	return nil
+}
+
+// Creates the rules of a class, numbering them in the order given.
+func createInflectionRules(ctx context.Context, tx store.Store, classID uuid.UUID, rules []inflection.Rule) error {
+	for i, rule := range rules {
+		_, err := tx.CreateInflectionRule(ctx, database.CreateInflectionRuleParams{
+			ClassID:     classID,
+			Position:    int32(i),
+			RowLabel:    rule.Row,
+			ColumnLabel: rule.Column,
+			Strip:       rule.Strip,
+			Prefix:      rule.Prefix,
+			Suffix:      rule.Suffix,
+		})
+		if err != nil {
+			return stepError(fmt.Sprintf("create rule %d", i), err, getFailedCreationCode(err))
+		}
+	}
+	return nil
+}
+
+// Fetches the rules of each class and marshals them together.
+func (cfg *apiConfig) getMarshallableInflectionClasses(
+	ctx context.Context,
+	classes []database.InflectionClass,
+) ([]InflectionClass, error) {
+	marshallable := []InflectionClass{}
+	for _, class := range classes {
+		rules, err := cfg.store.GetInflectionRulesOfClass(ctx, class.ID)
+		if err != nil {
+			return nil, err
+		}
+		marshallable = append(marshallable, getMarshallableInflectionClass(class, rules))
+	}
+	return marshallable, nil
+}
+
+// Resolves the inflection class identified by the `id` path parameter,
+// ensuring it belongs to the language in the `language` path parameter.
+// Writes the appropriate error response and returns false on failure.
+func (cfg *apiConfig) getInflectionClassFromPath(w http.ResponseWriter, r *http.Request) (database.InflectionClass, bool) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return database.InflectionClass{}, false
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError("Invalid inflection class ID", w, http.StatusBadRequest)
+		return database.InflectionClass{}, false
+	}
+
+	class, err := cfg.store.GetInflectionClassByID(r.Context(), id)
+	if err != nil || class.LanguageID != language.ID {
+		respondError("Inflection class not found", w, http.StatusNotFound)
+		return database.InflectionClass{}, false
+	}
+
+	return class, true
+}
+
+// Get every inflection class of the language in the path parameter, with
+// their rules.
+func (cfg *apiConfig) getInflectionClasses(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	classes, err := cfg.store.GetInflectionClassesOfLanguage(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve inflection classes", w, http.StatusInternalServerError)
+		return
+	}
+
+	marshallable, err := cfg.getMarshallableInflectionClasses(r.Context(), classes)
+	if err != nil {
+		respondError("Failed to retrieve inflection rules", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(marshallable, w, http.StatusOK)
+}
+
+// Get a single inflection class, with its rules.
+func (cfg *apiConfig) getInflectionClass(w http.ResponseWriter, r *http.Request) {
+	class, ok := cfg.getInflectionClassFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	rules, err := cfg.store.GetInflectionRulesOfClass(r.Context(), class.ID)
+	if err != nil {
+		respondError("Failed to retrieve inflection rules", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(getMarshallableInflectionClass(class, rules), w, http.StatusOK)
+}
+
+// Create an inflection class for the language in the path parameter. The
+// class and its rules are created in a single transaction.
+func (cfg *apiConfig) createInflectionClass(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	params := inflectionClassParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if err := params.validate(); err != nil {
+		respondError(fmt.Sprintf("Invalid request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	var class database.InflectionClass
+	var rules []database.InflectionRule
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		var err error
+		class, err = tx.CreateInflectionClass(r.Context(), database.CreateInflectionClassParams{
+			LanguageID:   language.ID,
+			PartOfSpeech: params.PartOfSpeech,
+			Name:         params.Name,
+		})
+		if err != nil {
+			return stepError("create inflection class", err, getFailedCreationCode(err))
+		}
+
+		if err := createInflectionRules(r.Context(), tx, class.ID, params.Rules); err != nil {
+			return err
+		}
+
+		rules, err = tx.GetInflectionRulesOfClass(r.Context(), class.ID)
+		return err
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	writeResponse(getMarshallableInflectionClass(class, rules), w, http.StatusCreated)
+}
+
+// Replace the part of speech, name and rules of an inflection class. Words
+// already assigned to the class keep it, along with their irregular forms.
+func (cfg *apiConfig) updateInflectionClass(w http.ResponseWriter, r *http.Request) {
+	class, ok := cfg.getInflectionClassFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := inflectionClassParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if err := params.validate(); err != nil {
+		respondError(fmt.Sprintf("Invalid request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	var rules []database.InflectionRule
+	err := cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		var err error
+		class, err = tx.UpdateInflectionClass(r.Context(), database.UpdateInflectionClassParams{
+			ID:           class.ID,
+			PartOfSpeech: params.PartOfSpeech,
+			Name:         params.Name,
+		})
+		if err != nil {
+			return stepError("update inflection class", err, getFailedCreationCode(err))
+		}
+
+		if err := tx.DeleteInflectionRulesOfClass(r.Context(), class.ID); err != nil {
+			return stepError("delete old rules", err, http.StatusInternalServerError)
+		}
+		if err := createInflectionRules(r.Context(), tx, class.ID, params.Rules); err != nil {
+			return err
+		}
+
+		rules, err = tx.GetInflectionRulesOfClass(r.Context(), class.ID)
+		return err
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	writeResponse(getMarshallableInflectionClass(class, rules), w, http.StatusOK)
+}
+
+// Delete an inflection class, along with its rules, and any word
+// assignments and irregular forms that depend on it.
+func (cfg *apiConfig) deleteInflectionClass(w http.ResponseWriter, r *http.Request) {
+	class, ok := cfg.getInflectionClassFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := cfg.store.DeleteInflectionClass(r.Context(), class.ID); err != nil {
+		respondError(fmt.Sprintf("Could not delete inflection class: %s", err), w, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
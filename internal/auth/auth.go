@@ -8,6 +8,10 @@ import (
 
 type AuthConfig struct {
 	ApiKey string
+	// The password of the web editor, which logs in a session rather than
+	// authorizing each request.
+	EditorPassword string
+	Sessions       *SessionStore
 }
 
 func GetAPIKey(headers http.Header) (string, error) {
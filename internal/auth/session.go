@@ -0,0 +1,342 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// The cookie holding the web editor's session ID.
+const SessionCookie = "vs_session"
+
+// The cookie holding the nonce that the login form's CSRF token is derived
+// from, before any session exists.
+const LoginCSRFCookie = "vs_login_csrf"
+
+// The form field every POST from the web editor must carry its CSRF token in.
+const CSRFField = "csrf_token"
+
+const (
+	// At most this many sessions are kept. Logging in beyond it ends the
+	// session closest to expiring.
+	maxSessions = 10000
+	// A client that fails to log in this many times within the window is
+	// refused until the window has passed since its first failure.
+	maxLoginFailures   = 5
+	loginFailureWindow = 15 * time.Minute
+	// Failures from all clients together within the window, beyond which
+	// every client is refused, so that spreading guesses across many
+	// addresses gains nothing.
+	maxTotalLoginFailures = 500
+	// At most this many clients' failures are tracked at once. Beyond it,
+	// the client tracked for longest is forgotten.
+	maxTrackedClients = 10000
+)
+
+// A logged in browser session of the web editor.
+type Session struct {
+	ID        string
+	CSRFToken string
+	Expires   time.Time
+}
+
+// The failed logins of one client within the current window.
+type loginFailures struct {
+	count int
+	since time.Time
+}
+
+// Sessions of the web editor, kept in memory, so they end when the server
+// restarts. They are entirely separate from the ApiKey scheme: a session
+// never authorizes an API request, and an API key never authorizes an edit.
+//
+// Sessions are only created by logging in. Until then, the login form is
+// protected against forgery by a double-submit cookie: the form's token is
+// an HMAC of a nonce in a cookie, under a key that never leaves the server,
+// so that visiting the login page stores nothing.
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+	lifetime time.Duration
+	csrfKey  []byte
+	failures map[string]loginFailures
+	// The failures of all clients together.
+	totalFailures loginFailures
+	// Whether cookies are only sent over HTTPS. The request alone can't tell,
+	// as TLS is usually terminated by a proxy in front of the server.
+	secure bool
+}
+
+func NewSessionStore(lifetime time.Duration, secure bool) *SessionStore {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+
+	return &SessionStore{
+		sessions: map[string]Session{},
+		lifetime: lifetime,
+		csrfKey:  key,
+		failures: map[string]loginFailures{},
+		secure:   secure,
+	}
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Gets the unexpired session named by the request's cookie.
+func (s *SessionStore) Get(r *http.Request) (Session, bool) {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return Session{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[cookie.Value]
+	if !ok || time.Now().After(session.Expires) {
+		delete(s.sessions, cookie.Value)
+		return Session{}, false
+	}
+	return session, true
+}
+
+// Replaces the request's session, if any, with a new one. The session ID
+// changes on every login, so that an ID planted before login is worthless
+// after.
+func (s *SessionStore) LogIn(w http.ResponseWriter, r *http.Request) Session {
+	s.end(r)
+	return s.create(w)
+}
+
+// Ends the request's session and clears its cookie.
+func (s *SessionStore) LogOut(w http.ResponseWriter, r *http.Request) {
+	s.end(r)
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (s *SessionStore) end(r *http.Request) {
+	if cookie, err := r.Cookie(SessionCookie); err == nil {
+		s.mu.Lock()
+		delete(s.sessions, cookie.Value)
+		s.mu.Unlock()
+	}
+}
+
+func (s *SessionStore) create(w http.ResponseWriter) Session {
+	session := Session{
+		ID:        randomToken(),
+		CSRFToken: randomToken(),
+		Expires:   time.Now().Add(s.lifetime),
+	}
+
+	s.mu.Lock()
+	if len(s.sessions) >= maxSessions {
+		// Expired sessions are only ever looked up by their own cookie, so
+		// they are swept out here instead.
+		now := time.Now()
+		for id, old := range s.sessions {
+			if now.After(old.Expires) {
+				delete(s.sessions, id)
+			}
+		}
+	}
+	if len(s.sessions) >= maxSessions {
+		soonest := ""
+		for id, old := range s.sessions {
+			if soonest == "" || old.Expires.Before(s.sessions[soonest].Expires) {
+				soonest = id
+			}
+		}
+		delete(s.sessions, soonest)
+	}
+	s.sessions[session.ID] = session
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    session.ID,
+		Path:     "/",
+		Expires:  session.Expires,
+		HttpOnly: true,
+		Secure:   s.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return session
+}
+
+func (s *SessionStore) signLoginNonce(nonce string) string {
+	mac := hmac.New(sha256.New, s.csrfKey)
+	mac.Write([]byte(nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Gets the CSRF token for a login form, setting the nonce cookie it is
+// derived from if the request has none.
+func (s *SessionStore) LoginCSRFToken(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(LoginCSRFCookie); err == nil && cookie.Value != "" {
+		return s.signLoginNonce(cookie.Value)
+	}
+
+	nonce := randomToken()
+	http.SetCookie(w, &http.Cookie{
+		Name:     LoginCSRFCookie,
+		Value:    nonce,
+		Path:     "/edit/login",
+		HttpOnly: true,
+		Secure:   s.secure,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return s.signLoginNonce(nonce)
+}
+
+// Whether the request's login form carries the token derived from its nonce
+// cookie.
+func (s *SessionStore) CheckLoginCSRF(r *http.Request) bool {
+	cookie, err := r.Cookie(LoginCSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	token := r.PostFormValue(CSRFField)
+	return hmac.Equal([]byte(token), []byte(s.signLoginNonce(cookie.Value)))
+}
+
+// The client that login failures are counted against. Forwarding headers are
+// ignored, as any client could set them.
+func loginClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// How long the request's client must wait before trying to log in again, or
+// zero if it may try now.
+func (s *SessionStore) LoginDelay(r *http.Request) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delay := time.Duration(0)
+	if s.totalFailures.count >= maxTotalLoginFailures {
+		delay = time.Until(s.totalFailures.since.Add(loginFailureWindow))
+	}
+	if failures, ok := s.failures[loginClient(r)]; ok && failures.count >= maxLoginFailures {
+		delay = max(delay, time.Until(failures.since.Add(loginFailureWindow)))
+	}
+	return max(delay, 0)
+}
+
+// Counts a failed login against the request's client.
+func (s *SessionStore) LoginFailed(r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.totalFailures.since) > loginFailureWindow {
+		s.totalFailures = loginFailures{since: now}
+	}
+	s.totalFailures.count++
+
+	client := loginClient(r)
+	failures, ok := s.failures[client]
+	if !ok || now.Sub(failures.since) > loginFailureWindow {
+		if len(s.failures) >= maxTrackedClients {
+			for c, f := range s.failures {
+				if now.Sub(f.since) > loginFailureWindow {
+					delete(s.failures, c)
+				}
+			}
+		}
+		if len(s.failures) >= maxTrackedClients {
+			oldest := ""
+			for c, f := range s.failures {
+				if oldest == "" || f.since.Before(s.failures[oldest].since) {
+					oldest = c
+				}
+			}
+			delete(s.failures, oldest)
+		}
+		failures = loginFailures{since: now}
+	}
+	failures.count++
+	s.failures[client] = failures
+}
+
+// Forgets the failed logins of the request's client, once it has logged in.
+func (s *SessionStore) LoginSucceeded(r *http.Request) {
+	s.mu.Lock()
+	delete(s.failures, loginClient(r))
+	s.mu.Unlock()
+}
+
+// Whether the request carries the session's CSRF token in its form.
+func (session Session) CheckCSRF(r *http.Request) bool {
+	token := r.PostFormValue(CSRFField)
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(session.CSRFToken)) == 1
+}
+
+// Whether password is the editor password. No password matches when none is
+// configured, which leaves the editor closed.
+func (cfg *AuthConfig) CheckEditorPassword(password string) bool {
+	if cfg.EditorPassword == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(password), []byte(cfg.EditorPassword)) == 1
+}
+
+type sessionKey struct{}
+
+// The session of a request that passed AuthenticateSession.
+func SessionFromContext(ctx context.Context) Session {
+	session, _ := ctx.Value(sessionKey{}).(Session)
+	return session
+}
+
+// Lets through only requests with a session, sending anyone else to
+// loginPath. Requests other than GET must also carry the session's CSRF
+// token, or are refused.
+func (cfg *AuthConfig) AuthenticateSession(loginPath string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, ok := cfg.Sessions.Get(r)
+		if !ok {
+			if r.Method == http.MethodGet {
+				http.Redirect(w, r, loginPath+"?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("Not authorized"))
+			return
+		}
+
+		if r.Method != http.MethodGet && !session.CheckCSRF(r) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Invalid CSRF token"))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionKey{}, session)))
+	})
+}
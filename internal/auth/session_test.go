@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCookiesFollowTheSecureSetting(t *testing.T) {
+	for _, secure := range []bool{true, false} {
+		sessions := NewSessionStore(time.Hour, secure)
+		w := httptest.NewRecorder()
+		// Plain HTTP, as seen behind a proxy that terminates TLS.
+		r := httptest.NewRequest("GET", "http://vastestsea.test/edit/login", nil)
+
+		sessions.LoginCSRFToken(w, r)
+		sessions.LogIn(w, r)
+		cookies := w.Result().Cookies()
+		if len(cookies) != 2 {
+			t.Fatalf("got cookies %v, want the login nonce and the session", cookies)
+		}
+		for _, cookie := range cookies {
+			if cookie.Secure != secure {
+				t.Errorf("with secure %t, got %s with Secure %t", secure, cookie.Name, cookie.Secure)
+			}
+		}
+	}
+}
+
+func loginFrom(addr string) *http.Request {
+	r := httptest.NewRequest("POST", "/edit/login", nil)
+	r.RemoteAddr = addr + ":1234"
+	return r
+}
+
+func TestLoginFailuresAreLimited(t *testing.T) {
+	sessions := NewSessionStore(time.Hour, true)
+	for range maxLoginFailures {
+		if sessions.LoginDelay(loginFrom("192.0.2.1")) != 0 {
+			t.Fatal("refused a client before it reached the limit")
+		}
+		sessions.LoginFailed(loginFrom("192.0.2.1"))
+	}
+	if sessions.LoginDelay(loginFrom("192.0.2.1")) == 0 {
+		t.Errorf("a client past the limit may still log in")
+	}
+	if sessions.LoginDelay(loginFrom("192.0.2.2")) != 0 {
+		t.Errorf("another client was refused")
+	}
+}
+
+func TestLoginFailuresAcrossClientsAreLimited(t *testing.T) {
+	sessions := NewSessionStore(time.Hour, true)
+	for i := range maxTrackedClients + 1 {
+		sessions.LoginFailed(loginFrom(fmt.Sprintf("10.%d.%d.%d", i>>16, i>>8&0xff, i&0xff)))
+	}
+	if len(sessions.failures) > maxTrackedClients {
+		t.Errorf("tracking %d clients, want at most %d", len(sessions.failures), maxTrackedClients)
+	}
+	if sessions.LoginDelay(loginFrom("192.0.2.1")) == 0 {
+		t.Errorf("a new client may still log in past the total limit")
+	}
+}
@@ -0,0 +1,94 @@
+package corpus
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// An occurrence of a keyword in a text, with the text around it. Left and
+// Right hold up to the requested number of tokens on either side, along with
+// the punctuation and spacing between them. Line breaks become spaces, so that
+// each occurrence reads as a single line.
+type Occurrence struct {
+	Line    int    `json:"line"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Left    string `json:"left"`
+	Keyword string `json:"keyword"`
+	Right   string `json:"right"`
+}
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// Finds every token of text whose form is form, keyword in context. Tokens
+// must be the tokens of text, and context is the number of tokens to keep on
+// each side.
+func KWIC(text string, tokens []Token, form string, context int) []Occurrence {
+	form = strings.ToLower(form)
+	occurrences := []Occurrence{}
+	for i, t := range tokens {
+		if t.Form != form {
+			continue
+		}
+
+		occurrence := Occurrence{
+			Line:    t.Line,
+			Start:   t.Start,
+			End:     t.End,
+			Keyword: t.Text,
+		}
+		if context > 0 {
+			first := max(i-context, 0)
+			last := min(i+context, len(tokens)-1)
+			if first < i {
+				occurrence.Left = lineBreaks.Replace(text[tokens[first].Start:t.Start])
+			}
+			if last > i {
+				occurrence.Right = lineBreaks.Replace(text[t.End:tokens[last].End])
+			}
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
+
+// How often each form occurs among tokens.
+func Count(tokens []Token) map[string]int {
+	counts := map[string]int{}
+	for _, t := range tokens {
+		counts[t.Form]++
+	}
+	return counts
+}
+
+// A form and its position when forms are ordered by how often they occur.
+type Rank struct {
+	Form  string `json:"form"`
+	Count int    `json:"count"`
+	Rank  int    `json:"rank"`
+}
+
+// Ranks forms by count, most frequent first. Forms with the same count share
+// a rank, and the next rank skips accordingly, so that ranks read as "1, 2,
+// 2, 4". Ties are listed alphabetically.
+func RankCounts(counts map[string]int) []Rank {
+	ranks := []Rank{}
+	for form, count := range counts {
+		if count > 0 {
+			ranks = append(ranks, Rank{Form: form, Count: count})
+		}
+	}
+	slices.SortFunc(ranks, func(a, b Rank) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Form, b.Form))
+	})
+
+	for i := range ranks {
+		if i > 0 && ranks[i].Count == ranks[i-1].Count {
+			ranks[i].Rank = ranks[i-1].Rank
+		} else {
+			ranks[i].Rank = i + 1
+		}
+	}
+	return ranks
+}
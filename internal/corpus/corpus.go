@@ -0,0 +1,87 @@
+// Package corpus splits texts written in a language into word tokens, for
+// linking them against the lexicon.
+package corpus
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A word of a text. Start and End are byte offsets into the text, and Form is
+// the token lowercased, as headwords are compared.
+type Token struct {
+	Text  string `json:"token"`
+	Form  string `json:"-"`
+	Line  int    `json:"line"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r)
+}
+
+// Apostrophes and hyphens are common in romanized conlangs, for glottal stops
+// and compounds, so they are kept when they fall between word characters.
+func isJoiner(r rune) bool {
+	return r == '\'' || r == '’' || r == '-'
+}
+
+// Splits text into tokens: runs of letters, marks and digits, optionally
+// joined by apostrophes or hyphens. Lines are numbered from zero.
+func Tokenize(text string) []Token {
+	tokens := []Token{}
+	line := 0
+	start := -1
+
+	emit := func(end int) {
+		tokens = append(tokens, Token{
+			Text:  text[start:end],
+			Form:  strings.ToLower(text[start:end]),
+			Line:  line,
+			Start: start,
+			End:   end,
+		})
+		start = -1
+	}
+
+	for i, r := range text {
+		switch {
+		case isWordRune(r):
+			if start < 0 {
+				start = i
+			}
+		case isJoiner(r) && start >= 0:
+			next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+			if !isWordRune(next) {
+				emit(i)
+			}
+		default:
+			if start >= 0 {
+				emit(i)
+			}
+			if r == '\n' {
+				line++
+			}
+		}
+	}
+	if start >= 0 {
+		emit(len(text))
+	}
+
+	return tokens
+}
+
+// The distinct forms of tokens, in order of first appearance.
+func Forms(tokens []Token) []string {
+	forms := []string{}
+	seen := map[string]bool{}
+	for _, t := range tokens {
+		if !seen[t.Form] {
+			seen[t.Form] = true
+			forms = append(forms, t.Form)
+		}
+	}
+	return forms
+}
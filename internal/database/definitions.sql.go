@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createDefinition = `-- name: CreateDefinition :one
@@ -109,9 +110,45 @@ func (q *Queries) GetDefinitionsOfWord(ctx context.Context, wordID uuid.UUID) ([
 	return items, nil
 }
 
+const getDefinitionsOfWords = `-- name: GetDefinitionsOfWords :many
+SELECT id, created_at, updated_at, content, part_of_speech, word_id FROM definitions
+WHERE definitions.word_id = ANY($1::uuid[])
+ORDER BY word_id, part_of_speech ASC, content ASC
+`
+
+func (q *Queries) GetDefinitionsOfWords(ctx context.Context, wordIds []uuid.UUID) ([]Definition, error) {
+	rows, err := q.db.QueryContext(ctx, getDefinitionsOfWords, pq.Array(wordIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Definition
+	for rows.Next() {
+		var i Definition
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Content,
+			&i.PartOfSpeech,
+			&i.WordID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateDefinition = `-- name: UpdateDefinition :one
 UPDATE definitions
-SET content = $1, part_of_speech = $2
+SET content = $1, part_of_speech = $2, updated_at = NOW()
 WHERE id = $3
 RETURNING id, created_at, updated_at, content, part_of_speech, word_id
 `
@@ -138,7 +175,7 @@ func (q *Queries) UpdateDefinition(ctx context.Context, arg UpdateDefinitionPara
 
 const updateDefinitionContent = `-- name: UpdateDefinitionContent :one
 UPDATE definitions
-SET content = $1
+SET content = $1, updated_at = NOW()
 WHERE id = $2
 RETURNING id, created_at, updated_at, content, part_of_speech, word_id
 `
@@ -164,7 +201,7 @@ func (q *Queries) UpdateDefinitionContent(ctx context.Context, arg UpdateDefinit
 
 const updateDefinitionPartOfSpeech = `-- name: UpdateDefinitionPartOfSpeech :one
 UPDATE definitions
-SET part_of_speech = $1
+SET part_of_speech = $1, updated_at = NOW()
 WHERE id = $2
 RETURNING id, created_at, updated_at, content, part_of_speech, word_id
 `
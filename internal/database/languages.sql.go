@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -110,9 +111,100 @@ func (q *Queries) GetLanguages(ctx context.Context) ([]Language, error) {
 	return items, nil
 }
 
+const listLanguagesAlphabetical = `-- name: ListLanguagesAlphabetical :many
+SELECT id, created_at, updated_at, name FROM languages
+WHERE (LOWER(name), id) > ($1::text, $2::uuid)
+ORDER BY LOWER(name), id
+LIMIT $3
+`
+
+type ListLanguagesAlphabeticalParams struct {
+	AfterName  string
+	AfterID    uuid.UUID
+	MaxResults int32
+}
+
+func (q *Queries) ListLanguagesAlphabetical(ctx context.Context, arg ListLanguagesAlphabeticalParams) ([]Language, error) {
+	rows, err := q.db.QueryContext(ctx, listLanguagesAlphabetical, arg.AfterName, arg.AfterID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Language
+	for rows.Next() {
+		var i Language
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLanguagesByTime = `-- name: ListLanguagesByTime :many
+SELECT id, created_at, updated_at, name FROM languages
+WHERE (
+    CASE WHEN $1::bool THEN updated_at ELSE created_at END,
+    id
+) > ($2::timestamp, $3::uuid)
+ORDER BY CASE WHEN $1::bool THEN updated_at ELSE created_at END, id
+LIMIT $4
+`
+
+type ListLanguagesByTimeParams struct {
+	ByUpdated  bool
+	AfterTime  time.Time
+	AfterID    uuid.UUID
+	MaxResults int32
+}
+
+func (q *Queries) ListLanguagesByTime(ctx context.Context, arg ListLanguagesByTimeParams) ([]Language, error) {
+	rows, err := q.db.QueryContext(ctx, listLanguagesByTime,
+		arg.ByUpdated,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Language
+	for rows.Next() {
+		var i Language
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateLanguageName = `-- name: UpdateLanguageName :one
 UPDATE languages
-SET name = $1
+SET name = $1, updated_at = NOW()
 WHERE LOWER(name) = $2
 RETURNING id, created_at, updated_at, name
 `
@@ -6,6 +6,7 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,50 @@ type Definition struct {
 	WordID       uuid.UUID
 }
 
+type Example struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	WordID       uuid.UUID
+	DefinitionID uuid.NullUUID
+	Source       string
+	Gloss        string
+	Translation  string
+}
+
+type InflectionClass struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	LanguageID   uuid.UUID
+	PartOfSpeech string
+	Name         string
+}
+
+type InflectionOverride struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	WordID      uuid.UUID
+	ClassID     uuid.UUID
+	RowLabel    string
+	ColumnLabel string
+	Form        string
+}
+
+type InflectionRule struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ClassID     uuid.UUID
+	Position    int32
+	RowLabel    string
+	ColumnLabel string
+	Strip       string
+	Prefix      string
+	Suffix      string
+}
+
 type Language struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
@@ -27,6 +72,46 @@ type Language struct {
 	Name      string
 }
 
+type LemmaRule struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	LanguageID uuid.UUID
+	Kind       string
+	Prefix     string
+	Suffix     string
+	Restore    string
+	Features   string
+}
+
+type Phonology struct {
+	LanguageID        uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Phonemes          json.RawMessage
+	Categories        json.RawMessage
+	SyllableTemplates []string
+	ForbiddenClusters []string
+}
+
+type Text struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	LanguageID uuid.UUID
+	Title      string
+	Content    string
+}
+
+type Translation struct {
+	ID                uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	WordID            uuid.UUID
+	TranslationWordID uuid.UUID
+	DefinitionID      uuid.NullUUID
+}
+
 type Word struct {
 	ID            uuid.UUID
 	CreatedAt     time.Time
@@ -35,3 +120,18 @@ type Word struct {
 	FontFormatted sql.NullString
 	LanguageID    uuid.UUID
 }
+
+type WordInflectionClass struct {
+	WordID    uuid.UUID
+	ClassID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type WordRelation struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	WordID       uuid.UUID
+	SourceWordID uuid.UUID
+	RelationType string
+}
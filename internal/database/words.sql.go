@@ -8,8 +8,10 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createFormattedWord = `-- name: CreateFormattedWord :one
@@ -198,6 +200,41 @@ func (q *Queries) GetWords(ctx context.Context) ([]Word, error) {
 	return items, nil
 }
 
+const getWordsByIDs = `-- name: GetWordsByIDs :many
+SELECT id, created_at, updated_at, word, font_formatted, language_id FROM words
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetWordsByIDs(ctx context.Context, ids []uuid.UUID) ([]Word, error) {
+	rows, err := q.db.QueryContext(ctx, getWordsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Word
+	for rows.Next() {
+		var i Word
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Word,
+			&i.FontFormatted,
+			&i.LanguageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWordsByLanguageID = `-- name: GetWordsByLanguageID :many
 SELECT id, created_at, updated_at, word, font_formatted, language_id FROM words
 WHERE language_id = $1
@@ -233,6 +270,151 @@ func (q *Queries) GetWordsByLanguageID(ctx context.Context, languageID uuid.UUID
 	return items, nil
 }
 
+const getWordsFromLanguageByForms = `-- name: GetWordsFromLanguageByForms :many
+SELECT id, created_at, updated_at, word, font_formatted, language_id FROM words
+WHERE language_id = $1 AND LOWER(word) = ANY($2::text[])
+`
+
+type GetWordsFromLanguageByFormsParams struct {
+	LanguageID uuid.UUID
+	Forms      []string
+}
+
+func (q *Queries) GetWordsFromLanguageByForms(ctx context.Context, arg GetWordsFromLanguageByFormsParams) ([]Word, error) {
+	rows, err := q.db.QueryContext(ctx, getWordsFromLanguageByForms, arg.LanguageID, pq.Array(arg.Forms))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Word
+	for rows.Next() {
+		var i Word
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Word,
+			&i.FontFormatted,
+			&i.LanguageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWordsAlphabetical = `-- name: ListWordsAlphabetical :many
+SELECT id, created_at, updated_at, word, font_formatted, language_id FROM words
+WHERE ($1::uuid IS NULL OR language_id = $1)
+    AND (LOWER(word), id) > ($2::text, $3::uuid)
+ORDER BY LOWER(word), id
+LIMIT $4
+`
+
+type ListWordsAlphabeticalParams struct {
+	LanguageID uuid.NullUUID
+	AfterWord  string
+	AfterID    uuid.UUID
+	MaxResults int32
+}
+
+func (q *Queries) ListWordsAlphabetical(ctx context.Context, arg ListWordsAlphabeticalParams) ([]Word, error) {
+	rows, err := q.db.QueryContext(ctx, listWordsAlphabetical,
+		arg.LanguageID,
+		arg.AfterWord,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Word
+	for rows.Next() {
+		var i Word
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Word,
+			&i.FontFormatted,
+			&i.LanguageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWordsByTime = `-- name: ListWordsByTime :many
+SELECT id, created_at, updated_at, word, font_formatted, language_id FROM words
+WHERE ($1::uuid IS NULL OR language_id = $1)
+    AND (
+        CASE WHEN $2::bool THEN updated_at ELSE created_at END,
+        id
+    ) > ($3::timestamp, $4::uuid)
+ORDER BY CASE WHEN $2::bool THEN updated_at ELSE created_at END, id
+LIMIT $5
+`
+
+type ListWordsByTimeParams struct {
+	LanguageID uuid.NullUUID
+	ByUpdated  bool
+	AfterTime  time.Time
+	AfterID    uuid.UUID
+	MaxResults int32
+}
+
+func (q *Queries) ListWordsByTime(ctx context.Context, arg ListWordsByTimeParams) ([]Word, error) {
+	rows, err := q.db.QueryContext(ctx, listWordsByTime,
+		arg.LanguageID,
+		arg.ByUpdated,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Word
+	for rows.Next() {
+		var i Word
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Word,
+			&i.FontFormatted,
+			&i.LanguageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateWord = `-- name: UpdateWord :one
 UPDATE words
 SET 
@@ -243,7 +425,8 @@ SET
     font_formatted = CASE WHEN $3::bool
         THEN $4::text
         ELSE font_formatted
-        END
+        END,
+    updated_at = NOW()
 WHERE id = $5
 RETURNING id, created_at, updated_at, word, font_formatted, language_id
 `
@@ -0,0 +1,107 @@
+// Package inflection builds the inflected forms of words from affix rules,
+// laid out as a paradigm table of rows and columns, such as person and number
+// by tense.
+package inflection
+
+import "strings"
+
+// Produces the form in one cell of a paradigm.
+type Rule struct {
+	Row    string `json:"row"`
+	Column string `json:"column"`
+	// An ending removed from the word before the affixes are added, such as
+	// the infinitive ending of a verb. Left alone if the word lacks it.
+	Strip  string `json:"strip"`
+	Prefix string `json:"prefix"`
+	Suffix string `json:"suffix"`
+}
+
+// Builds the form of word in the rule's cell. Affixes may be written with a
+// hyphen marking where they attach, as in `ge-` or `-uth`.
+func (r Rule) Apply(word string) string {
+	stem := strings.TrimSuffix(word, r.Strip)
+	return strings.TrimSuffix(r.Prefix, "-") + stem + strings.TrimPrefix(r.Suffix, "-")
+}
+
+// An irregular form, replacing whatever the rule for its cell would produce.
+type Override struct {
+	Row    string `json:"row"`
+	Column string `json:"column"`
+	Form   string `json:"form"`
+}
+
+type Cell struct {
+	Row       string `json:"row"`
+	Column    string `json:"column"`
+	Form      string `json:"form"`
+	Irregular bool   `json:"irregular"`
+}
+
+// Rows and Columns list the labels in the order they were first used.
+type Table struct {
+	Rows    []string `json:"rows"`
+	Columns []string `json:"columns"`
+	Cells   []Cell   `json:"cells"`
+}
+
+// Looks up the cell at row and column.
+func (t Table) Cell(row, column string) (Cell, bool) {
+	for _, c := range t.Cells {
+		if c.Row == row && c.Column == column {
+			return c, true
+		}
+	}
+	return Cell{}, false
+}
+
+// Builds the paradigm of word from the rules of its class, in order.
+// Overrides replace the generated form of their cell, or add a cell if no
+// rule covers it.
+func Build(word string, rules []Rule, overrides []Override) Table {
+	table := Table{
+		Rows:    []string{},
+		Columns: []string{},
+		Cells:   []Cell{},
+	}
+
+	seenRows := map[string]bool{}
+	seenColumns := map[string]bool{}
+	addLabels := func(row, column string) {
+		if !seenRows[row] {
+			seenRows[row] = true
+			table.Rows = append(table.Rows, row)
+		}
+		if !seenColumns[column] {
+			seenColumns[column] = true
+			table.Columns = append(table.Columns, column)
+		}
+	}
+
+	irregular := map[[2]string]string{}
+	for _, o := range overrides {
+		irregular[[2]string{o.Row, o.Column}] = o.Form
+	}
+
+	for _, r := range rules {
+		addLabels(r.Row, r.Column)
+		cell := Cell{Row: r.Row, Column: r.Column, Form: r.Apply(word)}
+		if form, ok := irregular[[2]string{r.Row, r.Column}]; ok {
+			cell.Form = form
+			cell.Irregular = true
+			delete(irregular, [2]string{r.Row, r.Column})
+		}
+		table.Cells = append(table.Cells, cell)
+	}
+
+	// Overrides for cells that no rule covers, kept in the order given.
+	for _, o := range overrides {
+		if _, ok := irregular[[2]string{o.Row, o.Column}]; !ok {
+			continue
+		}
+		addLabels(o.Row, o.Column)
+		table.Cells = append(table.Cells, Cell{Row: o.Row, Column: o.Column, Form: o.Form, Irregular: true})
+		delete(irregular, [2]string{o.Row, o.Column})
+	}
+
+	return table
+}
@@ -0,0 +1,130 @@
+package inflection
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Kinds of affix a StripRule removes.
+const (
+	PrefixAffix    = "prefix"
+	SuffixAffix    = "suffix"
+	CircumfixAffix = "circumfix"
+)
+
+// Undoes one affix: Prefix and/or Suffix are removed from a form, and
+// Restore is appended to what remains. Features labels the grammatical
+// meaning of the affix, such as "past 3pl".
+type StripRule struct {
+	Kind     string `json:"kind"`
+	Prefix   string `json:"prefix"`
+	Suffix   string `json:"suffix"`
+	Restore  string `json:"restore"`
+	Features string `json:"features"`
+}
+
+// Checks that the rule has the affixes its kind calls for, and only those.
+func (r StripRule) Check() error {
+	if r.Features == "" {
+		return fmt.Errorf("features are required")
+	}
+
+	switch r.Kind {
+	case PrefixAffix:
+		if r.Prefix == "" || r.Suffix != "" {
+			return fmt.Errorf("prefix rules need a prefix, and no suffix")
+		}
+	case SuffixAffix:
+		if r.Suffix == "" || r.Prefix != "" {
+			return fmt.Errorf("suffix rules need a suffix, and no prefix")
+		}
+	case CircumfixAffix:
+		if r.Prefix == "" || r.Suffix == "" {
+			return fmt.Errorf("circumfix rules need both a prefix and a suffix")
+		}
+	default:
+		return fmt.Errorf("kind must be one of %s, %s or %s", PrefixAffix, SuffixAffix, CircumfixAffix)
+	}
+
+	return nil
+}
+
+// Removes the rule's affixes from form. Fails if form lacks them, or if
+// nothing would be left of the stem.
+func (r StripRule) Strip(form string) (string, bool) {
+	prefix := strings.ToLower(strings.TrimSuffix(r.Prefix, "-"))
+	suffix := strings.ToLower(strings.TrimPrefix(r.Suffix, "-"))
+
+	if len(form) <= len(prefix)+len(suffix) ||
+		!strings.HasPrefix(form, prefix) ||
+		!strings.HasSuffix(form, suffix) {
+		return "", false
+	}
+
+	stem := form[len(prefix) : len(form)-len(suffix)]
+	return stem + strings.ToLower(r.Restore), true
+}
+
+// A possible lemma of a form, with the features of every affix stripped to
+// reach it, outermost first.
+type Candidate struct {
+	Lemma    string   `json:"lemma"`
+	Features []string `json:"features"`
+}
+
+// The most affixes stripped from a single form, bounding the search.
+const maxStrips = 3
+
+// Finds every form that stripping up to maxStrips affixes from form could
+// lead to, each rule being used at most once along the way. The form itself
+// comes first, with no features, followed by candidates in order of how
+// many affixes were stripped.
+func Lemmatize(form string, rules []StripRule) []Candidate {
+	type state struct {
+		Candidate
+		used map[int]bool
+	}
+
+	form = strings.ToLower(form)
+	candidates := []Candidate{{Lemma: form, Features: []string{}}}
+	seen := map[string]bool{form + "\x00": true}
+
+	frontier := []state{{Candidate: candidates[0], used: map[int]bool{}}}
+	for range maxStrips {
+		next := []state{}
+		for _, s := range frontier {
+			for i, rule := range rules {
+				if s.used[i] {
+					continue
+				}
+				lemma, ok := rule.Strip(s.Lemma)
+				if !ok {
+					continue
+				}
+
+				// Stripping the same affixes in a different order reaches the
+				// same analysis, so the key ignores order.
+				features := append(append([]string{}, s.Features...), rule.Features)
+				sorted := slices.Sorted(slices.Values(features))
+				key := lemma + "\x00" + strings.Join(sorted, "\x00")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				used := map[int]bool{i: true}
+				for j := range s.used {
+					used[j] = true
+				}
+
+				c := Candidate{Lemma: lemma, Features: features}
+				candidates = append(candidates, c)
+				next = append(next, state{Candidate: c, used: used})
+			}
+		}
+		frontier = next
+	}
+
+	return candidates
+}
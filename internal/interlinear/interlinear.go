@@ -0,0 +1,106 @@
+// Package interlinear aligns morpheme-segmented text with its gloss, following
+// the Leipzig Glossing Rules: words are separated by whitespace, morphemes
+// within a word by `-`, and clitics by `=`. Both lines must segment the same
+// way, so that every morpheme has exactly one gloss.
+package interlinear
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Morpheme struct {
+	Form  string `json:"form"`
+	Gloss string `json:"gloss"`
+}
+
+// A word of the source line, its gloss, and its morphemes aligned one to one.
+type Word struct {
+	Form      string     `json:"form"`
+	Gloss     string     `json:"gloss"`
+	Morphemes []Morpheme `json:"morphemes"`
+}
+
+// Describes where the source and gloss lines fail to line up. Word is the
+// zero-based index of the first misaligned word, or -1 when the lines have
+// different numbers of words.
+type AlignmentError struct {
+	Word    int    `json:"word"`
+	Message string `json:"message"`
+}
+
+func (e *AlignmentError) Error() string {
+	if e.Word < 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("word %d: %s", e.Word+1, e.Message)
+}
+
+// Splits a word at morpheme and clitic boundaries.
+func splitMorphemes(word string) []string {
+	return strings.FieldsFunc(word, func(r rune) bool {
+		return r == '-' || r == '='
+	})
+}
+
+// The boundary characters of a word, in order, so that both lines can be
+// checked to use the same kind of boundary in the same place.
+func boundaries(word string) string {
+	var b strings.Builder
+	for _, r := range word {
+		if r == '-' || r == '=' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Aligns source and gloss word by word and morpheme by morpheme, failing
+// with an *AlignmentError if their segment counts differ anywhere.
+func Align(source, gloss string) ([]Word, error) {
+	sourceWords := strings.Fields(source)
+	glossWords := strings.Fields(gloss)
+
+	if len(sourceWords) == 0 {
+		return nil, &AlignmentError{Word: -1, Message: "source text is empty"}
+	}
+	if len(sourceWords) != len(glossWords) {
+		return nil, &AlignmentError{
+			Word:    -1,
+			Message: fmt.Sprintf("source has %d words but gloss has %d", len(sourceWords), len(glossWords)),
+		}
+	}
+
+	words := []Word{}
+	for i := range sourceWords {
+		forms := splitMorphemes(sourceWords[i])
+		glosses := splitMorphemes(glossWords[i])
+		if len(forms) != len(glosses) {
+			return nil, &AlignmentError{
+				Word: i,
+				Message: fmt.Sprintf(
+					"%q has %d morphemes but its gloss %q has %d",
+					sourceWords[i], len(forms), glossWords[i], len(glosses),
+				),
+			}
+		}
+		if boundaries(sourceWords[i]) != boundaries(glossWords[i]) {
+			return nil, &AlignmentError{
+				Word:    i,
+				Message: fmt.Sprintf("%q and its gloss %q use different boundaries", sourceWords[i], glossWords[i]),
+			}
+		}
+
+		word := Word{
+			Form:      sourceWords[i],
+			Gloss:     glossWords[i],
+			Morphemes: []Morpheme{},
+		}
+		for j := range forms {
+			word.Morphemes = append(word.Morphemes, Morpheme{Form: forms[j], Gloss: glosses[j]})
+		}
+		words = append(words, word)
+	}
+
+	return words, nil
+}
@@ -0,0 +1,436 @@
+// Package lift reads and writes LIFT (Lexicon Interchange FormaT), the XML
+// format used by SIL FieldWorks and WeSay. Only the parts of LIFT that have a
+// home in the dictionary are mapped: entries, their senses, grammatical info,
+// glosses, definitions and examples. Anything else found while reading is
+// counted in a Report, so that callers can tell what an import leaves behind.
+package lift
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The LIFT version written by Encode.
+const Version = "0.13"
+
+// The entry field holding a word's font-formatted rendering, which LIFT has
+// no element for.
+const FontFormattedField = "font-formatted"
+
+// The example field holding an interlinear gloss, which LIFT has no element
+// for.
+const InterlinearGlossField = "interlinear-gloss"
+
+// The part of speech given to senses that have no grammatical info.
+const UnknownPartOfSpeech = "unknown"
+
+// Any element or attribute that has no field of its own. Only its name is
+// kept, for reporting.
+type unknown struct {
+	XMLName xml.Name
+}
+
+type Lift struct {
+	XMLName  xml.Name   `xml:"lift"`
+	Version  string     `xml:"version,attr"`
+	Producer string     `xml:"producer,attr,omitempty"`
+	Entries  []Entry    `xml:"entry"`
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Other    []unknown  `xml:",any"`
+}
+
+type Entry struct {
+	ID           string     `xml:"id,attr,omitempty"`
+	GUID         string     `xml:"guid,attr,omitempty"`
+	DateCreated  string     `xml:"dateCreated,attr,omitempty"`
+	DateModified string     `xml:"dateModified,attr,omitempty"`
+	DateDeleted  string     `xml:"dateDeleted,attr,omitempty"`
+	LexicalUnit  *Multitext `xml:"lexical-unit"`
+	Senses       []Sense    `xml:"sense"`
+	Fields       []Field    `xml:"field"`
+	Attrs        []xml.Attr `xml:",any,attr"`
+	Other        []unknown  `xml:",any"`
+}
+
+type Sense struct {
+	ID              string           `xml:"id,attr,omitempty"`
+	GrammaticalInfo *GrammaticalInfo `xml:"grammatical-info"`
+	Glosses         []Form           `xml:"gloss"`
+	Definition      *Multitext       `xml:"definition"`
+	Examples        []Example        `xml:"example"`
+	Attrs           []xml.Attr       `xml:",any,attr"`
+	Other           []unknown        `xml:",any"`
+}
+
+type GrammaticalInfo struct {
+	Value string     `xml:"value,attr"`
+	Attrs []xml.Attr `xml:",any,attr"`
+	Other []unknown  `xml:",any"`
+}
+
+type Example struct {
+	Forms        []Form        `xml:"form"`
+	Translations []Translation `xml:"translation"`
+	Fields       []Field       `xml:"field"`
+	Attrs        []xml.Attr    `xml:",any,attr"`
+	Other        []unknown     `xml:",any"`
+}
+
+type Translation struct {
+	Type  string     `xml:"type,attr,omitempty"`
+	Forms []Form     `xml:"form"`
+	Attrs []xml.Attr `xml:",any,attr"`
+	Other []unknown  `xml:",any"`
+}
+
+type Field struct {
+	Type  string     `xml:"type,attr"`
+	Forms []Form     `xml:"form"`
+	Attrs []xml.Attr `xml:",any,attr"`
+	Other []unknown  `xml:",any"`
+}
+
+type Multitext struct {
+	Forms []Form     `xml:"form"`
+	Attrs []xml.Attr `xml:",any,attr"`
+	Other []unknown  `xml:",any"`
+}
+
+// Text in one writing system, identified by its language tag.
+type Form struct {
+	Lang  string     `xml:"lang,attr"`
+	Text  string     `xml:"text"`
+	Attrs []xml.Attr `xml:",any,attr"`
+	Other []unknown  `xml:",any"`
+}
+
+// Reads a LIFT document.
+func Decode(r io.Reader) (*Lift, error) {
+	doc := &Lift{}
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Writes a LIFT document, indented, with its XML declaration.
+func Encode(w io.Writer, doc *Lift) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// A word as the dictionary stores it, independent of LIFT.
+type Lexeme struct {
+	ID            string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Word          string
+	FontFormatted string
+	Senses        []LexemeSense
+}
+
+// A definition of a Lexeme. Definitions that have no sense of their own in
+// the dictionary, such as examples attached to no definition, have an empty
+// Definition.
+type LexemeSense struct {
+	ID           string
+	PartOfSpeech string
+	Definition   string
+	Examples     []LexemeExample
+}
+
+type LexemeExample struct {
+	Source      string
+	Gloss       string
+	Translation string
+}
+
+// A kind of element or attribute that was not mapped, and how many times it
+// occurred. Paths are written from the entry down, such as
+// `entry/sense/note` or `entry/@dateCreated`.
+type UnmappedField struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+// Collects what reading a document left unmapped, along with warnings about
+// entries and senses that were only partly usable.
+type Report struct {
+	counts   map[string]int
+	Warnings []string
+}
+
+func (r *Report) add(path string) {
+	if r.counts == nil {
+		r.counts = map[string]int{}
+	}
+	r.counts[path]++
+}
+
+func (r *Report) warn(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (r *Report) addAll(path string, attrs []xml.Attr, other []unknown) {
+	for _, attr := range attrs {
+		r.add(path + "/@" + qualifiedName(attr.Name))
+	}
+	for _, element := range other {
+		r.add(path + "/" + qualifiedName(element.XMLName))
+	}
+}
+
+// The unmapped fields, sorted by path.
+func (r *Report) Unmapped() []UnmappedField {
+	fields := []UnmappedField{}
+	for path, count := range r.counts {
+		fields = append(fields, UnmappedField{Path: path, Count: count})
+	}
+	slices.SortFunc(fields, func(a, b UnmappedField) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return fields
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" || name.Space == "xml" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// Picks the form written in lang, or the first form if lang is empty or
+// missing. The others are reported as unmapped.
+func (r *Report) pick(path string, forms []Form, lang string) string {
+	chosen := -1
+	for i, form := range forms {
+		if form.Lang == lang {
+			chosen = i
+			break
+		}
+	}
+	if chosen < 0 && len(forms) > 0 {
+		chosen = 0
+	}
+
+	for i, form := range forms {
+		r.addAll(path+"/form", form.Attrs, form.Other)
+		if i != chosen {
+			r.add(fmt.Sprintf("%s/form[@lang=%q]", path, form.Lang))
+		}
+	}
+	if chosen < 0 {
+		return ""
+	}
+	return strings.TrimSpace(forms[chosen].Text)
+}
+
+// Maps the entries of a document onto lexemes. Headwords are read in the
+// vernacular writing system, and everything else in the analysis writing
+// system; either may be empty to take the first form given.
+func (doc *Lift) Lexicon(vernacular, analysis string) ([]Lexeme, *Report) {
+	report := &Report{}
+	report.addAll("lift", doc.Attrs, doc.Other)
+
+	lexemes := []Lexeme{}
+	for i, entry := range doc.Entries {
+		// FieldWorks keeps deleted entries around, marked with the date they
+		// were deleted on.
+		if entry.DateDeleted != "" {
+			report.warn("entry %d (%s) was deleted on %s and was skipped", i+1, entryName(entry), entry.DateDeleted)
+			continue
+		}
+
+		report.addAll("entry", entry.Attrs, entry.Other)
+		if entry.DateCreated != "" {
+			report.add("entry/@dateCreated")
+		}
+		if entry.DateModified != "" {
+			report.add("entry/@dateModified")
+		}
+
+		lexeme := Lexeme{ID: entry.GUID}
+		if entry.LexicalUnit != nil {
+			report.addAll("entry/lexical-unit", entry.LexicalUnit.Attrs, entry.LexicalUnit.Other)
+			lexeme.Word = report.pick("entry/lexical-unit", entry.LexicalUnit.Forms, vernacular)
+		}
+		if lexeme.Word == "" {
+			report.warn("entry %d (%s) has no lexical unit and was skipped", i+1, entryName(entry))
+			continue
+		}
+
+		for _, field := range entry.Fields {
+			report.addAll("entry/field", field.Attrs, field.Other)
+			if field.Type == FontFormattedField && lexeme.FontFormatted == "" {
+				lexeme.FontFormatted = report.pick("entry/field", field.Forms, vernacular)
+				continue
+			}
+			report.add(fmt.Sprintf("entry/field[@type=%q]", field.Type))
+		}
+
+		for _, sense := range entry.Senses {
+			lexeme.Senses = append(lexeme.Senses, readSense(report, lexeme.Word, sense, vernacular, analysis))
+		}
+
+		lexemes = append(lexemes, lexeme)
+	}
+
+	return lexemes, report
+}
+
+func entryName(entry Entry) string {
+	if entry.ID != "" {
+		return entry.ID
+	}
+	if entry.GUID != "" {
+		return entry.GUID
+	}
+	return "no id"
+}
+
+// A sense becomes a definition, worded by its definition or, failing that,
+// by its glosses. Glosses alongside a definition have nowhere to go, and are
+// reported. A sense with neither only carries its examples, as exported for
+// examples attached to no definition.
+func readSense(report *Report, word string, sense Sense, vernacular, analysis string) LexemeSense {
+	report.addAll("entry/sense", sense.Attrs, sense.Other)
+
+	s := LexemeSense{ID: sense.ID}
+	if sense.GrammaticalInfo != nil {
+		report.addAll("entry/sense/grammatical-info", sense.GrammaticalInfo.Attrs, sense.GrammaticalInfo.Other)
+		s.PartOfSpeech = strings.TrimSpace(sense.GrammaticalInfo.Value)
+	}
+	if sense.Definition != nil {
+		report.addAll("entry/sense/definition", sense.Definition.Attrs, sense.Definition.Other)
+		s.Definition = report.pick("entry/sense/definition", sense.Definition.Forms, analysis)
+	}
+
+	glosses := []string{}
+	for _, gloss := range sense.Glosses {
+		report.addAll("entry/sense/gloss", gloss.Attrs, gloss.Other)
+		if analysis != "" && gloss.Lang != analysis {
+			report.add(fmt.Sprintf("entry/sense/gloss[@lang=%q]", gloss.Lang))
+			continue
+		}
+		if text := strings.TrimSpace(gloss.Text); text != "" {
+			glosses = append(glosses, text)
+		}
+	}
+	if s.Definition == "" {
+		s.Definition = strings.Join(glosses, "; ")
+	} else {
+		for range glosses {
+			report.add("entry/sense/gloss")
+		}
+	}
+	if s.Definition != "" && s.PartOfSpeech == "" {
+		s.PartOfSpeech = UnknownPartOfSpeech
+		report.warn("a sense of %q has no grammatical info, and was given the part of speech %q", word, UnknownPartOfSpeech)
+	}
+
+	for _, example := range sense.Examples {
+		report.addAll("entry/sense/example", example.Attrs, example.Other)
+		e := LexemeExample{Source: report.pick("entry/sense/example", example.Forms, vernacular)}
+		for i, translation := range example.Translations {
+			report.addAll("entry/sense/example/translation", translation.Attrs, translation.Other)
+			if i > 0 {
+				report.add("entry/sense/example/translation")
+				continue
+			}
+			e.Translation = report.pick("entry/sense/example/translation", translation.Forms, analysis)
+		}
+		for _, field := range example.Fields {
+			report.addAll("entry/sense/example/field", field.Attrs, field.Other)
+			if field.Type == InterlinearGlossField && e.Gloss == "" {
+				e.Gloss = report.pick("entry/sense/example/field", field.Forms, analysis)
+				continue
+			}
+			report.add(fmt.Sprintf("entry/sense/example/field[@type=%q]", field.Type))
+		}
+
+		if e.Source == "" {
+			report.warn("an example of %q has no text and was skipped", word)
+			continue
+		}
+		s.Examples = append(s.Examples, e)
+	}
+
+	if s.Definition == "" && len(s.Examples) == 0 {
+		report.warn("a sense of %q has neither a definition, a gloss nor an example", word)
+	}
+
+	return s
+}
+
+// Builds a LIFT document from lexemes, writing headwords in the vernacular
+// writing system and everything else in the analysis writing system.
+func FromLexicon(lexemes []Lexeme, vernacular, analysis, producer string) *Lift {
+	doc := &Lift{
+		Version:  Version,
+		Producer: producer,
+		Entries:  []Entry{},
+	}
+
+	for _, lexeme := range lexemes {
+		entry := Entry{
+			ID:           lexeme.Word + "_" + lexeme.ID,
+			GUID:         lexeme.ID,
+			DateCreated:  formatTime(lexeme.CreatedAt),
+			DateModified: formatTime(lexeme.UpdatedAt),
+			LexicalUnit:  &Multitext{Forms: []Form{{Lang: vernacular, Text: lexeme.Word}}},
+		}
+		if lexeme.FontFormatted != "" {
+			entry.Fields = append(entry.Fields, Field{
+				Type:  FontFormattedField,
+				Forms: []Form{{Lang: vernacular, Text: lexeme.FontFormatted}},
+			})
+		}
+
+		for _, s := range lexeme.Senses {
+			sense := Sense{ID: s.ID}
+			if s.PartOfSpeech != "" {
+				sense.GrammaticalInfo = &GrammaticalInfo{Value: s.PartOfSpeech}
+			}
+			if s.Definition != "" {
+				sense.Definition = &Multitext{Forms: []Form{{Lang: analysis, Text: s.Definition}}}
+			}
+			for _, e := range s.Examples {
+				example := Example{Forms: []Form{{Lang: vernacular, Text: e.Source}}}
+				if e.Translation != "" {
+					example.Translations = []Translation{{Forms: []Form{{Lang: analysis, Text: e.Translation}}}}
+				}
+				if e.Gloss != "" {
+					example.Fields = []Field{{
+						Type:  InterlinearGlossField,
+						Forms: []Form{{Lang: analysis, Text: e.Gloss}},
+					}}
+				}
+				sense.Examples = append(sense.Examples, example)
+			}
+			entry.Senses = append(entry.Senses, sense)
+		}
+
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return doc
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
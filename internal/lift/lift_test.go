@@ -0,0 +1,81 @@
+package lift
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExampleOnlySensesRoundTrip(t *testing.T) {
+	lexemes := []Lexeme{{
+		ID:   "1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+		Word: "mellon",
+		Senses: []LexemeSense{
+			{ID: "a", PartOfSpeech: "noun", Definition: "friend"},
+			{Examples: []LexemeExample{{Source: "pedo mellon a minno", Translation: "speak, friend, and enter"}}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, FromLexicon(lexemes, "qya", "en", "test")); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read, report := doc.Lexicon("qya", "en")
+	if len(report.Warnings) != 0 {
+		t.Errorf("got warnings %q, want none", report.Warnings)
+	}
+	if len(read) != 1 || len(read[0].Senses) != 2 {
+		t.Fatalf("got %+v, want one lexeme with two senses", read)
+	}
+	if examples := read[0].Senses[1].Examples; len(examples) != 1 || examples[0].Source != "pedo mellon a minno" {
+		t.Errorf("got examples %+v on the example-only sense", examples)
+	}
+}
+
+func TestEmptySensesWarn(t *testing.T) {
+	doc, err := Decode(strings.NewReader(`<lift version="0.13">
+  <entry id="mellon"><lexical-unit><form lang="qya"><text>mellon</text></form></lexical-unit><sense/></entry>
+</lift>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, report := doc.Lexicon("qya", "en")
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "neither a definition") {
+		t.Errorf("got warnings %q, want one about the empty sense", report.Warnings)
+	}
+}
+
+func TestDeletedEntriesAreSkipped(t *testing.T) {
+	doc, err := Decode(strings.NewReader(`<lift version="0.13">
+  <entry id="mellon" dateDeleted="2024-03-01T12:00:00Z">
+    <lexical-unit><form lang="qya"><text>mellon</text></form></lexical-unit>
+    <sense><grammatical-info value="noun"/><gloss lang="en"><text>friend</text></gloss></sense>
+  </entry>
+  <entry id="meldo">
+    <lexical-unit><form lang="qya"><text>meldo</text></form></lexical-unit>
+    <sense><grammatical-info value="noun"/><gloss lang="en"><text>friend</text></gloss></sense>
+  </entry>
+</lift>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lexemes, report := doc.Lexicon("qya", "en")
+	if len(lexemes) != 1 || lexemes[0].Word != "meldo" {
+		t.Fatalf("got %+v, want only meldo", lexemes)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "deleted") {
+		t.Errorf("got warnings %q, want one about the deleted entry", report.Warnings)
+	}
+	for _, field := range report.Unmapped() {
+		if strings.Contains(field.Path, "dateDeleted") {
+			t.Errorf("dateDeleted reported as unmapped")
+		}
+	}
+}
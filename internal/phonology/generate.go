@@ -0,0 +1,185 @@
+package phonology
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// A syllable template, and how often it is picked relative to the others.
+// A template with a weight of 0 is never picked.
+type WeightedTemplate struct {
+	Template string  `json:"template"`
+	Weight   float64 `json:"weight"`
+}
+
+// Decodes a weighted template, whose weight defaults to 1 when it is left out.
+func (t *WeightedTemplate) UnmarshalJSON(data []byte) error {
+	type plain WeightedTemplate
+	decoded := plain{Weight: 1}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*t = WeightedTemplate(decoded)
+	return nil
+}
+
+// Builds random words out of syllables drawn from weighted templates.
+type Generator struct {
+	templates [][]templateElement
+	// Running totals of the template weights, for picking a template.
+	cumulative   []float64
+	banned       []string
+	minSyllables int
+	maxSyllables int
+}
+
+// Prepares a generator for the inventory. Templates with a weight of 0 are
+// checked but left out, and if no templates are given at all the inventory's
+// own syllable templates are used with equal weight.
+func NewGenerator(
+	inv Inventory,
+	templates []WeightedTemplate,
+	minSyllables int,
+	maxSyllables int,
+) (*Generator, error) {
+	if err := inv.Check(); err != nil {
+		return nil, err
+	}
+	if minSyllables < 1 || maxSyllables < minSyllables {
+		return nil, fmt.Errorf("syllable counts must satisfy 1 <= min <= max")
+	}
+
+	if len(templates) == 0 {
+		for _, t := range inv.SyllableTemplates {
+			templates = append(templates, WeightedTemplate{Template: t, Weight: 1})
+		}
+	}
+	if len(templates) == 0 {
+		return nil, errors.New("at least one syllable template is required")
+	}
+
+	g := &Generator{
+		minSyllables: minSyllables,
+		maxSyllables: maxSyllables,
+	}
+
+	categories := inv.categories()
+	total := 0.0
+	for _, t := range templates {
+		if t.Weight < 0 {
+			return nil, fmt.Errorf("syllable template %q has a negative weight", t.Template)
+		}
+		elems, err := parseTemplate(t.Template, categories)
+		if err != nil {
+			return nil, fmt.Errorf("syllable template %q: %w", t.Template, err)
+		}
+		if !canGenerate(elems) {
+			return nil, fmt.Errorf("syllable template %q uses an empty category", t.Template)
+		}
+
+		if t.Weight == 0 {
+			continue
+		}
+		total += t.Weight
+		g.templates = append(g.templates, elems)
+		g.cumulative = append(g.cumulative, total)
+	}
+	if total == 0 {
+		return nil, errors.New("at least one syllable template must have a positive weight")
+	}
+
+	for _, cluster := range inv.ForbiddenClusters {
+		g.banned = append(g.banned, strings.ToLower(cluster))
+	}
+
+	return g, nil
+}
+
+// Whether every required slot of the template has something to fill it.
+func canGenerate(elems []templateElement) bool {
+	for _, e := range elems {
+		if e.optional == nil && e.members != nil && len(e.members) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Generates up to count distinct words. Words containing a banned cluster, or
+// for which exclude returns true, are skipped. The same seed always produces
+// the same words, though fewer than count may be returned if the templates
+// cannot produce enough distinct words.
+func (g *Generator) Generate(count int, seed int64, exclude func(string) bool) []string {
+	rng := rand.New(rand.NewSource(seed))
+
+	words := []string{}
+	seen := map[string]bool{}
+	for attempts := 0; len(words) < count && attempts < count*100; attempts++ {
+		word := g.word(rng)
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+
+		if g.isBanned(word) || (exclude != nil && exclude(word)) {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return words
+}
+
+func (g *Generator) isBanned(word string) bool {
+	for _, cluster := range g.banned {
+		if strings.Contains(word, cluster) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Generator) word(rng *rand.Rand) string {
+	var b strings.Builder
+
+	syllables := g.minSyllables + rng.Intn(g.maxSyllables-g.minSyllables+1)
+	for range syllables {
+		pick := rng.Float64() * g.cumulative[len(g.cumulative)-1]
+		i, _ := slices.BinarySearch(g.cumulative, pick)
+		if i == len(g.templates) {
+			i--
+		}
+		fillTemplate(g.templates[i], rng, &b)
+	}
+
+	return b.String()
+}
+
+// Writes a random instance of the template. Optional slots are filled half of
+// the time.
+func fillTemplate(elems []templateElement, rng *rand.Rand, b *strings.Builder) {
+	for _, e := range elems {
+		switch {
+		case e.optional != nil:
+			if rng.Intn(2) == 0 {
+				fillTemplate(e.optional, rng, b)
+			}
+		case e.members != nil:
+			// Map iteration order is random, so sort for reproducible picks.
+			members := []string{}
+			for member := range e.members {
+				members = append(members, member)
+			}
+			slices.Sort(members)
+			if len(members) > 0 {
+				b.WriteString(members[rng.Intn(len(members))])
+			}
+		default:
+			b.WriteString(e.literal)
+		}
+	}
+}
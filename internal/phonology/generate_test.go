@@ -0,0 +1,32 @@
+package phonology
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTemplateWeights(t *testing.T) {
+	templates := []WeightedTemplate{}
+	body := `[{"template": "CV"}, {"template": "V", "weight": 0}]`
+	if err := json.Unmarshal([]byte(body), &templates); err != nil {
+		t.Fatal(err)
+	}
+	if templates[0].Weight != 1 || templates[1].Weight != 0 {
+		t.Fatalf("got %+v, want an omitted weight of 1 and an explicit 0", templates)
+	}
+
+	inv := Inventory{Phonemes: []Phoneme{{IPA: "p", Kind: Consonant}, {IPA: "a", Kind: Vowel}}}
+	g, err := NewGenerator(inv, templates, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range g.Generate(5, 1, nil) {
+		if word != "pa" {
+			t.Errorf("generated %q from a template weighted 0", word)
+		}
+	}
+
+	if _, err := NewGenerator(inv, templates[1:], 1, 1); err == nil {
+		t.Errorf("got a generator with every template weighted 0")
+	}
+}
@@ -0,0 +1,234 @@
+// Package phonology describes a language's sound system, its phoneme
+// inventory, syllable structure and forbidden clusters, and checks words
+// against it.
+package phonology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	Consonant = "consonant"
+	Vowel     = "vowel"
+)
+
+type Phoneme struct {
+	IPA          string `json:"ipa"`
+	Romanization string `json:"romanization"`
+	Kind         string `json:"kind"`
+}
+
+// How the phoneme is spelled in headwords. Falls back on the IPA when no
+// romanization is given.
+func (p Phoneme) Spelling() string {
+	if p.Romanization != "" {
+		return p.Romanization
+	}
+	return p.IPA
+}
+
+type Inventory struct {
+	Phonemes []Phoneme `json:"phonemes"`
+	// Extra categories for use in syllable templates, by single uppercase
+	// letter, listing member spellings. C and V default to the consonants and
+	// vowels of the inventory.
+	Categories        map[string][]string `json:"categories"`
+	SyllableTemplates []string            `json:"syllable_templates"`
+	ForbiddenClusters []string            `json:"forbidden_clusters"`
+}
+
+// Describes where, and why, a word breaks the phonotactics of an inventory.
+// Position is a character offset into the word.
+type ValidationError struct {
+	Position int    `json:"position"`
+	Segment  string `json:"segment,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Position, e.Message)
+}
+
+// Checks that the inventory is well formed, and that its templates parse.
+func (inv Inventory) Check() error {
+	for i, p := range inv.Phonemes {
+		if p.IPA == "" {
+			return fmt.Errorf("phoneme %d has no IPA", i)
+		}
+		if p.Kind != Consonant && p.Kind != Vowel {
+			return fmt.Errorf("phoneme %q must be a %s or a %s", p.IPA, Consonant, Vowel)
+		}
+	}
+
+	for name := range inv.Categories {
+		if r, size := utf8.DecodeRuneInString(name); size != len(name) || !unicode.IsUpper(r) {
+			return fmt.Errorf("category name %q must be a single uppercase letter", name)
+		}
+	}
+
+	categories := inv.categories()
+	for _, template := range inv.SyllableTemplates {
+		if _, err := parseTemplate(template, categories); err != nil {
+			return fmt.Errorf("syllable template %q: %w", template, err)
+		}
+	}
+
+	for _, cluster := range inv.ForbiddenClusters {
+		if cluster == "" {
+			return fmt.Errorf("forbidden clusters cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// All categories available to templates, including the default C and V.
+func (inv Inventory) categories() map[string]map[string]bool {
+	categories := map[string]map[string]bool{
+		"C": {},
+		"V": {},
+	}
+	for _, p := range inv.Phonemes {
+		if p.Kind == Consonant {
+			categories["C"][strings.ToLower(p.Spelling())] = true
+		} else {
+			categories["V"][strings.ToLower(p.Spelling())] = true
+		}
+	}
+	for name, members := range inv.Categories {
+		categories[name] = map[string]bool{}
+		for _, member := range members {
+			categories[name][strings.ToLower(member)] = true
+		}
+	}
+	return categories
+}
+
+type segment struct {
+	text     string
+	position int
+}
+
+// Splits a word into phonemes by their spelling, preferring the longest
+// match at each point.
+func (inv Inventory) Segment(word string) ([]string, error) {
+	segments, err := inv.segment(strings.ToLower(word))
+	if err != nil {
+		return nil, err
+	}
+
+	texts := []string{}
+	for _, s := range segments {
+		texts = append(texts, s.text)
+	}
+	return texts, nil
+}
+
+func (inv Inventory) segment(word string) ([]segment, error) {
+	spellings := []string{}
+	for _, p := range inv.Phonemes {
+		spellings = append(spellings, strings.ToLower(p.Spelling()))
+	}
+	sort.SliceStable(spellings, func(i, j int) bool {
+		return len(spellings[i]) > len(spellings[j])
+	})
+
+	segments := []segment{}
+	position := 0
+	for rest := word; rest != ""; {
+		matched := ""
+		for _, spelling := range spellings {
+			if spelling != "" && strings.HasPrefix(rest, spelling) {
+				matched = spelling
+				break
+			}
+		}
+		if matched == "" {
+			r, _ := utf8.DecodeRuneInString(rest)
+			return nil, &ValidationError{
+				Position: position,
+				Segment:  string(r),
+				Message:  fmt.Sprintf("%q is not in the phoneme inventory", string(r)),
+			}
+		}
+
+		segments = append(segments, segment{text: matched, position: position})
+		position += utf8.RuneCountInString(matched)
+		rest = rest[len(matched):]
+	}
+
+	return segments, nil
+}
+
+// Checks a headword against the inventory: every part of it must be a known
+// phoneme, it must not contain a forbidden cluster, and it must divide into
+// syllables matching the templates. Returns a *ValidationError on failure.
+func (inv Inventory) Validate(word string) error {
+	lower := strings.ToLower(word)
+
+	segments, err := inv.segment(lower)
+	if err != nil {
+		return err
+	}
+
+	for _, cluster := range inv.ForbiddenClusters {
+		if i := strings.Index(lower, strings.ToLower(cluster)); i >= 0 {
+			return &ValidationError{
+				Position: utf8.RuneCountInString(lower[:i]),
+				Segment:  cluster,
+				Message:  fmt.Sprintf("%q is a forbidden cluster", cluster),
+			}
+		}
+	}
+
+	if len(inv.SyllableTemplates) == 0 || len(segments) == 0 {
+		return nil
+	}
+
+	categories := inv.categories()
+	templates := [][]templateElement{}
+	for _, t := range inv.SyllableTemplates {
+		template, err := parseTemplate(t, categories)
+		if err != nil {
+			return err
+		}
+		templates = append(templates, template)
+	}
+
+	// reachable[i] is true when segments[:i] divides into whole syllables.
+	reachable := make([]bool, len(segments)+1)
+	reachable[0] = true
+	furthest := 0
+	for i := range segments {
+		if !reachable[i] {
+			continue
+		}
+		for _, template := range templates {
+			for _, end := range matchTemplate(template, segments, i) {
+				if end > i {
+					reachable[end] = true
+					furthest = max(furthest, end)
+				}
+			}
+		}
+	}
+
+	if !reachable[len(segments)] {
+		// The first segment no syllable could reach is the one at fault.
+		offending := segments[furthest]
+		return &ValidationError{
+			Position: offending.position,
+			Segment:  offending.text,
+			Message: fmt.Sprintf(
+				"cannot divide into syllables matching %s",
+				strings.Join(inv.SyllableTemplates, ", "),
+			),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package phonology
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// One slot of a syllable template: a category, a literal phoneme, or an
+// optional group of slots.
+type templateElement struct {
+	members  map[string]bool
+	literal  string
+	optional []templateElement
+}
+
+// Parses a template such as `(C)V(C)` or `(s)CV(N)`. Uppercase letters name
+// categories, parentheses mark optional slots, and anything else is a literal
+// phoneme spelling.
+func parseTemplate(template string, categories map[string]map[string]bool) ([]templateElement, error) {
+	elems, rest, err := parseTemplateElements([]rune(template), categories, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected %q", string(rest))
+	}
+	if len(elems) == 0 {
+		return nil, fmt.Errorf("template is empty")
+	}
+	return elems, nil
+}
+
+func parseTemplateElements(
+	runes []rune,
+	categories map[string]map[string]bool,
+	inGroup bool,
+) ([]templateElement, []rune, error) {
+	elems := []templateElement{}
+	for len(runes) > 0 {
+		r := runes[0]
+		switch {
+		case unicode.IsSpace(r):
+			runes = runes[1:]
+
+		case r == '(':
+			sub, rest, err := parseTemplateElements(runes[1:], categories, true)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(rest) == 0 || rest[0] != ')' {
+				return nil, nil, fmt.Errorf("unterminated `(`")
+			}
+			elems = append(elems, templateElement{optional: sub})
+			runes = rest[1:]
+
+		case r == ')':
+			if !inGroup {
+				return nil, nil, fmt.Errorf("unmatched `)`")
+			}
+			return elems, runes, nil
+
+		case unicode.IsUpper(r):
+			members, ok := categories[string(r)]
+			if !ok {
+				return nil, nil, fmt.Errorf("undefined category %q", string(r))
+			}
+			elems = append(elems, templateElement{members: members})
+			runes = runes[1:]
+
+		default:
+			elems = append(elems, templateElement{literal: strings.ToLower(string(r))})
+			runes = runes[1:]
+		}
+	}
+
+	if inGroup {
+		return nil, nil, fmt.Errorf("unterminated `(`")
+	}
+	return elems, runes, nil
+}
+
+// Returns every segment index a match of the template starting at start could
+// end at.
+func matchTemplate(elems []templateElement, segments []segment, start int) []int {
+	if len(elems) == 0 {
+		return []int{start}
+	}
+
+	e, rest := elems[0], elems[1:]
+	ends := []int{}
+	switch {
+	case e.optional != nil:
+		for _, mid := range matchTemplate(e.optional, segments, start) {
+			ends = append(ends, matchTemplate(rest, segments, mid)...)
+		}
+		ends = append(ends, matchTemplate(rest, segments, start)...)
+	case start >= len(segments):
+		// Nothing left to match a required slot against.
+	case e.members != nil:
+		if e.members[segments[start].text] {
+			ends = append(ends, matchTemplate(rest, segments, start+1)...)
+		}
+	default:
+		if segments[start].text == e.literal {
+			ends = append(ends, matchTemplate(rest, segments, start+1)...)
+		}
+	}
+
+	return ends
+}
@@ -0,0 +1,51 @@
+package printing
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("dictionary").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Georgia, serif; font-size: 11pt; margin: 2em auto; max-width: 60em; }
+h1 { text-align: center; }
+nav { text-align: center; margin-bottom: 2em; }
+nav a { margin: 0 0.25em; }
+section { columns: 2; column-gap: 2em; }
+h2 { column-span: all; border-bottom: 1px solid; }
+.entry { margin: 0 0 0.5em; text-indent: -1em; padding-left: 1em; break-inside: avoid; }
+.headword { font-weight: bold; }
+.part-of-speech, .example-source { font-style: italic; }
+.sense-number { font-weight: bold; }
+@media print {
+  nav { display: none; }
+  section { break-before: page; }
+  section:first-of-type { break-before: auto; }
+}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<nav>{{range .Letters}}<a href="#letter-{{.Letter}}">{{.Letter}}</a>{{end}}</nav>
+{{range .Letters}}<section id="letter-{{.Letter}}">
+<h2>{{.Letter}}</h2>
+{{range .Entries}}<p class="entry"><span class="headword">{{.Headword}}</span>
+{{- if .FontFormatted}} <span class="font-formatted">{{.FontFormatted}}</span>{{end}}
+{{- range .Groups}}{{if .PartOfSpeech}} <span class="part-of-speech">{{.PartOfSpeech}}</span>{{end}}
+{{- range .Senses}} {{if .Number}}<span class="sense-number">{{.Number}}.</span> {{end}}{{.Definition}}{{template "examples" .Examples}}{{end}}
+{{- end}}{{template "examples" .Examples}}</p>
+{{end}}</section>
+{{end}}</body>
+</html>
+{{define "examples"}}{{range .}} <span class="example-source">{{.Source}}</span>{{if .Translation}} ‘{{.Translation}}’{{end}}{{end}}{{end}}`))
+
+// Writes the dictionary as a standalone HTML page, styled for screen and
+// print. Font-formatted forms are marked with the font-formatted class, so
+// that a stylesheet can give them the language's own font.
+func (d Dictionary) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, d)
+}
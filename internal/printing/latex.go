@@ -0,0 +1,70 @@
+package printing
+
+import (
+	"io"
+	"strings"
+	"text/template"
+)
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// Escapes text so that LaTeX prints it as is.
+func escapeLaTeX(text string) string {
+	return latexEscaper.Replace(text)
+}
+
+// Delimited by << and >>, as braces are everywhere in LaTeX.
+var latexTemplate = template.Must(template.New("dictionary").
+	Delims("<<", ">>").
+	Funcs(template.FuncMap{"tex": escapeLaTeX}).
+	Parse(`% Compile with XeLaTeX or LuaLaTeX, so that any script can be typeset.
+\documentclass[10pt,twocolumn]{article}
+\usepackage{fontspec}
+\usepackage[margin=2cm]{geometry}
+
+% Redefine \fontformatted to typeset font-formatted forms in the language's
+% own font, for example with \newfontfamily.
+\newcommand{\fontformatted}[1]{#1}
+\newcommand{\headword}[1]{\textbf{#1}}
+\newcommand{\partofspeech}[1]{\textit{#1}}
+\newcommand{\sensenumber}[1]{\textbf{#1.}}
+\newcommand{\example}[2]{\textit{#1}\ifx&#2&\else{} ‘#2’\fi}
+\newcommand{\letter}[1]{\section*{#1}\markboth{#1}{#1}}
+
+\setlength{\parindent}{0pt}
+\setlength{\parskip}{0.4em}
+
+\title{<<tex .Title>>}
+\date{}
+
+\begin{document}
+\maketitle
+<<range .Letters>>
+\letter{<<tex .Letter>>}
+<<range .Entries>>
+\hangindent=1em\headword{<<tex .Headword>>}
+<<- if .FontFormatted>> \fontformatted{<<tex .FontFormatted>>}<<end>>
+<<- range .Groups>><<if .PartOfSpeech>> \partofspeech{<<tex .PartOfSpeech>>}<<end>>
+<<- range .Senses>> <<if .Number>>\sensenumber{<<.Number>>} <<end>><<tex .Definition>><<template "examples" .Examples>><<end>>
+<<- end>><<template "examples" .Examples>>
+<<end>><<end>>
+\end{document}
+<<define "examples">><<range .>> \example{<<tex .Source>>}{<<tex .Translation>>}<<end>><<end>>`))
+
+// Writes the dictionary as a LaTeX document. The preamble defines a command
+// for each part of an entry, so that the layout can be changed without
+// editing the entries.
+func (d Dictionary) WriteLaTeX(w io.Writer) error {
+	return latexTemplate.Execute(w, d)
+}
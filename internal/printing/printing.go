@@ -0,0 +1,134 @@
+// Package printing lays out a language's words as a printed dictionary would,
+// and renders it as standalone HTML or as LaTeX source. Entries are
+// alphabetized and grouped under their initial letter, and the senses of each
+// entry are grouped and numbered by part of speech.
+package printing
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// A word to be printed, with its senses in the order they should appear.
+// Examples holds those of no particular sense.
+type Entry struct {
+	Headword      string
+	FontFormatted string
+	Senses        []Sense
+	Examples      []Example
+}
+
+type Sense struct {
+	PartOfSpeech string
+	Definition   string
+	Examples     []Example
+}
+
+type Example struct {
+	Source      string
+	Translation string
+}
+
+// A dictionary laid out for printing.
+type Dictionary struct {
+	Title   string
+	Letters []Letter
+}
+
+// The entries whose headwords start with a letter.
+type Letter struct {
+	Letter  string
+	Entries []Article
+}
+
+// An entry as printed, with consecutive senses of the same part of speech
+// grouped together.
+type Article struct {
+	Headword      string
+	FontFormatted string
+	Groups        []SenseGroup
+	Examples      []Example
+}
+
+// Senses are numbered within their part of speech, and only when there is
+// more than one, so Number is 0 for a sense that stands alone.
+type SenseGroup struct {
+	PartOfSpeech string
+	Senses       []NumberedSense
+}
+
+type NumberedSense struct {
+	Number int
+	Sense
+}
+
+// The letter a headword is filed under: its first letter, in upper case.
+// Headwords without any letter are filed under "#".
+func initial(headword string) string {
+	for _, r := range headword {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToUpper(r))
+		}
+	}
+	return "#"
+}
+
+// Lays out entries as a dictionary. Entries are sorted by the letter they are
+// filed under, so that an affix such as "-ka" is printed among the K entries,
+// and then by headword regardless of case. Senses keep their order, so a
+// sense group begins wherever the part of speech changes.
+func New(title string, entries []Entry) Dictionary {
+	entries = slices.Clone(entries)
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return cmp.Or(
+			strings.Compare(initial(a.Headword), initial(b.Headword)),
+			strings.Compare(strings.ToLower(a.Headword), strings.ToLower(b.Headword)),
+			strings.Compare(a.Headword, b.Headword),
+		)
+	})
+
+	dictionary := Dictionary{Title: title, Letters: []Letter{}}
+	letters := map[string]int{}
+	for _, entry := range entries {
+		letter := initial(entry.Headword)
+		i, ok := letters[letter]
+		if !ok {
+			i = len(dictionary.Letters)
+			letters[letter] = i
+			dictionary.Letters = append(dictionary.Letters, Letter{Letter: letter})
+		}
+		dictionary.Letters[i].Entries = append(dictionary.Letters[i].Entries, newArticle(entry))
+	}
+
+	return dictionary
+}
+
+func newArticle(entry Entry) Article {
+	article := Article{
+		Headword:      entry.Headword,
+		FontFormatted: entry.FontFormatted,
+		Examples:      entry.Examples,
+	}
+
+	for i := 0; i < len(entry.Senses); {
+		group := i + 1
+		for group < len(entry.Senses) && entry.Senses[group].PartOfSpeech == entry.Senses[i].PartOfSpeech {
+			group++
+		}
+
+		senseGroup := SenseGroup{PartOfSpeech: entry.Senses[i].PartOfSpeech}
+		for n, sense := range entry.Senses[i:group] {
+			numbered := NumberedSense{Sense: sense}
+			if group-i > 1 {
+				numbered.Number = n + 1
+			}
+			senseGroup.Senses = append(senseGroup.Senses, numbered)
+		}
+		article.Groups = append(article.Groups, senseGroup)
+		i = group
+	}
+
+	return article
+}
@@ -0,0 +1,29 @@
+package printing
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLettersFollowTheirEntries(t *testing.T) {
+	entries := []Entry{}
+	for _, headword := range []string{"apple", "-ka", "kiri", "Bob", "123"} {
+		entries = append(entries, Entry{Headword: headword})
+	}
+
+	got := map[string][]string{}
+	order := []string{}
+	for _, letter := range New("test", entries).Letters {
+		order = append(order, letter.Letter)
+		for _, article := range letter.Entries {
+			got[letter.Letter] = append(got[letter.Letter], article.Headword)
+		}
+	}
+
+	if want := []string{"#", "A", "B", "K"}; !slices.Equal(order, want) {
+		t.Errorf("got letters %q, want %q", order, want)
+	}
+	if want := []string{"-ka", "kiri"}; !slices.Equal(got["K"], want) {
+		t.Errorf("got K entries %q, want %q", got["K"], want)
+	}
+}
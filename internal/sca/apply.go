@@ -0,0 +1,200 @@
+package sca
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A rule that changed a word, and the word before and after it applied.
+type Step struct {
+	Rule   string `json:"rule"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type Result struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+	Trace  []Step `json:"trace"`
+}
+
+// Runs every rule, in order, over word. The seed decides which words
+// optional rules apply to, so the same seed always gives the same result.
+func (rs *RuleSet) Apply(word string, seed int64) Result {
+	result := Result{
+		Input:  word,
+		Output: word,
+		Trace:  []Step{},
+	}
+
+	for i, rule := range rs.Rules {
+		if rule.nonce != nil && !rule.nonce[strings.ToLower(word)] {
+			continue
+		}
+		if rule.chance < 100 && roll(seed, word, i) >= rule.chance {
+			continue
+		}
+
+		changed := rule.apply(result.Output)
+		if changed != result.Output {
+			result.Trace = append(result.Trace, Step{
+				Rule:   rule.Source,
+				Before: result.Output,
+				After:  changed,
+			})
+			result.Output = changed
+		}
+	}
+
+	return result
+}
+
+// A deterministic number in [0, 100) for the given seed, word and rule.
+func roll(seed int64, word string, rule int) int {
+	h := fnv.New64a()
+	h.Write([]byte(strconv.FormatInt(seed, 10)))
+	h.Write([]byte{0})
+	h.Write([]byte(word))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(rule)))
+	return int(h.Sum64() % 100)
+}
+
+// Applies a single rule. Matches are found left to right without overlapping,
+// and environments are always checked against the word as it was before the
+// rule, so every change happens simultaneously.
+func (rule Rule) apply(word string) string {
+	var out strings.Builder
+
+	pos := 0
+	for pos <= len(word) {
+		end, replacement, ok := rule.matchAt(word, pos)
+		if ok {
+			out.WriteString(replacement)
+		}
+
+		// Insertions consume nothing, so the character at pos is kept.
+		if !ok || end == pos {
+			if pos == len(word) {
+				break
+			}
+			_, size := utf8.DecodeRuneInString(word[pos:])
+			out.WriteString(word[pos : pos+size])
+			end = pos + size
+		}
+		pos = end
+	}
+
+	return out.String()
+}
+
+// Finds the longest match at pos, among every alternative, whose environment
+// holds. Ties go to the alternative written first, so `[t th] > [d ð]` turns
+// "th" into "ð" rather than "dh".
+func (rule Rule) matchAt(word string, pos int) (int, string, bool) {
+	best, replacement, found := 0, "", false
+	for _, alt := range rule.alternatives {
+		ends := matchForward(alt.target, word, pos)
+		slices.Sort(ends)
+		slices.Reverse(ends)
+
+		for _, end := range ends {
+			if found && end <= best {
+				break
+			}
+			// Empty matches are only allowed for insertions.
+			if end == pos && len(alt.target) > 0 {
+				continue
+			}
+			if rule.environment != nil && !rule.environment.matches(word, pos, end) {
+				continue
+			}
+			if rule.exception != nil && rule.exception.matches(word, pos, end) {
+				continue
+			}
+			best, replacement, found = end, alt.replacement, true
+			break
+		}
+	}
+
+	return best, replacement, found
+}
+
+func (env *environment) matches(word string, start, end int) bool {
+	return len(matchBackward(env.before, word, start)) > 0 &&
+		len(matchForward(env.after, word, end)) > 0
+}
+
+// Returns every position a match of elems starting at pos could end at.
+func matchForward(elems []element, word string, pos int) []int {
+	if len(elems) == 0 {
+		return []int{pos}
+	}
+
+	e, rest := elems[0], elems[1:]
+	ends := []int{}
+	switch e.kind {
+	case literalElement:
+		if strings.HasPrefix(word[pos:], e.text) {
+			ends = append(ends, matchForward(rest, word, pos+len(e.text))...)
+		}
+	case categoryElement:
+		for _, member := range e.members {
+			if strings.HasPrefix(word[pos:], member) {
+				ends = append(ends, matchForward(rest, word, pos+len(member))...)
+			}
+		}
+	case boundaryElement:
+		if pos == len(word) {
+			ends = append(ends, matchForward(rest, word, pos)...)
+		}
+	case optionalElement:
+		for _, mid := range matchForward(e.sub, word, pos) {
+			ends = append(ends, matchForward(rest, word, mid)...)
+		}
+		ends = append(ends, matchForward(rest, word, pos)...)
+	}
+
+	return uniqueInts(ends)
+}
+
+// Returns every position a match of elems ending at pos could start at.
+func matchBackward(elems []element, word string, pos int) []int {
+	if len(elems) == 0 {
+		return []int{pos}
+	}
+
+	e, rest := elems[len(elems)-1], elems[:len(elems)-1]
+	starts := []int{}
+	switch e.kind {
+	case literalElement:
+		if strings.HasSuffix(word[:pos], e.text) {
+			starts = append(starts, matchBackward(rest, word, pos-len(e.text))...)
+		}
+	case categoryElement:
+		for _, member := range e.members {
+			if strings.HasSuffix(word[:pos], member) {
+				starts = append(starts, matchBackward(rest, word, pos-len(member))...)
+			}
+		}
+	case boundaryElement:
+		if pos == 0 {
+			starts = append(starts, matchBackward(rest, word, pos)...)
+		}
+	case optionalElement:
+		for _, mid := range matchBackward(e.sub, word, pos) {
+			starts = append(starts, matchBackward(rest, word, mid)...)
+		}
+		starts = append(starts, matchBackward(rest, word, pos)...)
+	}
+
+	return uniqueInts(starts)
+}
+
+func uniqueInts(values []int) []int {
+	slices.Sort(values)
+	return slices.Compact(values)
+}
@@ -0,0 +1,398 @@
+// Package sca implements a sound change applier: an ordered list of rewrite
+// rules, in the notation conlangers and historical linguists commonly use,
+// applied to words to derive their descendant forms.
+//
+// A rule set is written one statement per line:
+//
+//	; Comments start with a semicolon
+//	V=aeiou          ; a category, one member per character
+//	F=th sh ch f     ; members separated by spaces may be longer
+//	a > e / _i       ; a becomes e before i
+//	p, t, k > b, d, g / V_V
+//	[ptk] > [bdg] / V_V   ; same as above, mapping members by position
+//	V > / _#         ; word-final vowels are lost
+//	∅ > e / #_sC     ; e is inserted word-initially before s + consonant
+//	h > / _ // a_    ; h is lost, except after a
+//	?30 u > o / _#   ; an optional rule, applied to 30% of words
+//	o > u / _n {kon tor} ; a nonce rule, applied only to the listed words
+//
+// Environments mark the position of the target with `_`, and may use
+// literals, categories, `[...]` inline categories, `#` for a word boundary
+// and `(...)` for optional elements.
+package sca
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type elementKind int
+
+const (
+	literalElement elementKind = iota
+	categoryElement
+	boundaryElement
+	optionalElement
+)
+
+type element struct {
+	kind    elementKind
+	text    string
+	members []string
+	sub     []element
+}
+
+// One target and what it is replaced with. Rules such as `p, t, k > b, d, g`
+// have several alternatives.
+type alternative struct {
+	target      []element
+	replacement string
+}
+
+type environment struct {
+	before []element
+	after  []element
+}
+
+type Rule struct {
+	// The rule as written, used to identify it in traces.
+	Source string
+
+	alternatives []alternative
+	environment  *environment
+	exception    *environment
+
+	// Percentage of words an optional rule applies to. 100 for rules that
+	// always apply.
+	chance int
+	// Words a nonce rule is restricted to. Nil for rules that apply to every
+	// word.
+	nonce map[string]bool
+}
+
+type RuleSet struct {
+	Categories map[string][]string
+	Rules      []Rule
+}
+
+// Describes a line of a rule set that could not be parsed.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// Parses a rule set. Categories must be defined before the rules that use
+// them.
+func Parse(text string) (*RuleSet, error) {
+	rs := &RuleSet{
+		Categories: map[string][]string{},
+		Rules:      []Rule{},
+	}
+
+	for i, line := range strings.Split(text, "\n") {
+		lineNumber := i + 1
+		if comment := strings.Index(line, ";"); comment >= 0 {
+			line = line[:comment]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if name, members, ok := parseCategoryLine(line); ok {
+			expanded := []string{}
+			for _, member := range members {
+				if sub, ok := rs.Categories[member]; ok {
+					expanded = append(expanded, sub...)
+				} else {
+					expanded = append(expanded, member)
+				}
+			}
+			rs.Categories[name] = expanded
+			continue
+		}
+
+		rule, err := rs.parseRule(line)
+		if err != nil {
+			return nil, &ParseError{Line: lineNumber, Msg: err.Error()}
+		}
+		rs.Rules = append(rs.Rules, rule)
+	}
+
+	return rs, nil
+}
+
+// Recognizes `X=members`, where X is a single uppercase letter.
+func parseCategoryLine(line string) (string, []string, bool) {
+	name, members, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", nil, false
+	}
+	name = strings.TrimSpace(name)
+	if r, size := utf8.DecodeRuneInString(name); size != len(name) || !unicode.IsUpper(r) {
+		return "", nil, false
+	}
+
+	return name, splitMembers(strings.TrimSpace(members)), true
+}
+
+// Members are separated by spaces or commas when any are present, and are
+// single characters otherwise.
+func splitMembers(s string) []string {
+	if strings.ContainsAny(s, " ,\t") {
+		return strings.FieldsFunc(s, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
+
+	members := []string{}
+	for _, r := range s {
+		members = append(members, string(r))
+	}
+	return members
+}
+
+func (rs *RuleSet) parseRule(line string) (Rule, error) {
+	rule := Rule{
+		Source: line,
+		chance: 100,
+	}
+
+	// Optional rules: `?` or `?N` prefix.
+	if rest, ok := strings.CutPrefix(line, "?"); ok {
+		digits := 0
+		for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+			digits++
+		}
+		rule.chance = 50
+		if digits > 0 {
+			n, _ := strconv.Atoi(rest[:digits])
+			if n < 0 || n > 100 {
+				return Rule{}, fmt.Errorf("optional rule chance must be between 0 and 100")
+			}
+			rule.chance = n
+		}
+		line = strings.TrimSpace(rest[digits:])
+	}
+
+	// Nonce rules: trailing `{word word}`.
+	if open := strings.LastIndex(line, "{"); open >= 0 {
+		if !strings.HasSuffix(line, "}") {
+			return Rule{}, fmt.Errorf("unterminated nonce word list")
+		}
+		rule.nonce = map[string]bool{}
+		for _, word := range splitMembers(line[open+1 : len(line)-1]) {
+			rule.nonce[strings.ToLower(word)] = true
+		}
+		line = strings.TrimSpace(line[:open])
+	}
+
+	change, exception, hasException := strings.Cut(line, "//")
+	change, env, hasEnv := strings.Cut(change, "/")
+
+	target, replacement, ok := strings.Cut(change, ">")
+	if !ok {
+		target, replacement, ok = strings.Cut(change, "→")
+	}
+	if !ok {
+		return Rule{}, fmt.Errorf("expected `>` in %q", line)
+	}
+
+	alternatives, err := rs.parseChange(target, replacement)
+	if err != nil {
+		return Rule{}, err
+	}
+	rule.alternatives = alternatives
+
+	if hasEnv {
+		rule.environment, err = rs.parseEnvironment(env)
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+	if hasException {
+		rule.exception, err = rs.parseEnvironment(exception)
+		if err != nil {
+			return Rule{}, fmt.Errorf("exception: %w", err)
+		}
+	}
+
+	return rule, nil
+}
+
+// Pairs up comma separated targets and replacements. A single replacement is
+// shared by every target, and a lone category maps onto a category of the
+// same size member by member.
+func (rs *RuleSet) parseChange(target, replacement string) ([]alternative, error) {
+	targets := strings.Split(target, ",")
+	replacements := strings.Split(replacement, ",")
+	if len(replacements) != 1 && len(replacements) != len(targets) {
+		return nil, fmt.Errorf("%d targets but %d replacements", len(targets), len(replacements))
+	}
+
+	alternatives := []alternative{}
+	for i, t := range targets {
+		r := replacements[0]
+		if len(replacements) > 1 {
+			r = replacements[i]
+		}
+
+		targetElems, err := rs.parsePattern(t)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range targetElems {
+			if e.kind == boundaryElement || e.kind == optionalElement {
+				return nil, fmt.Errorf("targets cannot contain boundaries or optional elements")
+			}
+		}
+		replacementElems, err := rs.parsePattern(r)
+		if err != nil {
+			return nil, err
+		}
+
+		// Category to category, e.g. [ptk] > [bdg]
+		if len(targetElems) == 1 && len(replacementElems) == 1 &&
+			targetElems[0].kind == categoryElement && replacementElems[0].kind == categoryElement {
+			from, to := targetElems[0].members, replacementElems[0].members
+			if len(from) != len(to) {
+				return nil, fmt.Errorf("cannot map %d category members onto %d", len(from), len(to))
+			}
+			for j := range from {
+				alternatives = append(alternatives, alternative{
+					target:      []element{{kind: literalElement, text: from[j]}},
+					replacement: to[j],
+				})
+			}
+			continue
+		}
+
+		var replacementText strings.Builder
+		for _, e := range replacementElems {
+			if e.kind != literalElement {
+				return nil, fmt.Errorf("replacements may only contain literals, or a single category mapped from a target category")
+			}
+			replacementText.WriteString(e.text)
+		}
+
+		alternatives = append(alternatives, alternative{
+			target:      targetElems,
+			replacement: replacementText.String(),
+		})
+	}
+
+	return alternatives, nil
+}
+
+func (rs *RuleSet) parseEnvironment(env string) (*environment, error) {
+	before, after, ok := strings.Cut(env, "_")
+	if !ok {
+		return nil, fmt.Errorf("environment %q has no `_`", strings.TrimSpace(env))
+	}
+	if strings.Contains(after, "_") {
+		return nil, fmt.Errorf("environment %q has more than one `_`", strings.TrimSpace(env))
+	}
+
+	beforeElems, err := rs.parsePattern(before)
+	if err != nil {
+		return nil, err
+	}
+	afterElems, err := rs.parsePattern(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &environment{before: beforeElems, after: afterElems}, nil
+}
+
+// Parses a sequence of pattern elements. Whitespace is insignificant, and
+// `∅` stands for nothing.
+func (rs *RuleSet) parsePattern(s string) ([]element, error) {
+	runes := []rune(s)
+	elems, rest, err := rs.parseElements(runes, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected %q", string(rest))
+	}
+	return elems, nil
+}
+
+// Parses elements until the input, or the enclosing optional group, ends.
+func (rs *RuleSet) parseElements(runes []rune, inGroup bool) ([]element, []rune, error) {
+	elems := []element{}
+	for len(runes) > 0 {
+		r := runes[0]
+		switch {
+		case unicode.IsSpace(r) || r == '∅':
+			runes = runes[1:]
+
+		case r == '#':
+			elems = append(elems, element{kind: boundaryElement})
+			runes = runes[1:]
+
+		case r == '(':
+			sub, rest, err := rs.parseElements(runes[1:], true)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(rest) == 0 || rest[0] != ')' {
+				return nil, nil, fmt.Errorf("unterminated `(`")
+			}
+			elems = append(elems, element{kind: optionalElement, sub: sub})
+			runes = rest[1:]
+
+		case r == '{' || r == '}':
+			return nil, nil, fmt.Errorf("unexpected `%c`: a rule takes one nonce word list, at its end", r)
+
+		case r == ')':
+			if !inGroup {
+				return nil, nil, fmt.Errorf("unmatched `)`")
+			}
+			return elems, runes, nil
+
+		case r == '[':
+			end := -1
+			for i, c := range runes {
+				if c == ']' {
+					end = i
+					break
+				}
+			}
+			if end < 0 {
+				return nil, nil, fmt.Errorf("unterminated `[`")
+			}
+			members := []string{}
+			for _, member := range splitMembers(string(runes[1:end])) {
+				if sub, ok := rs.Categories[member]; ok {
+					members = append(members, sub...)
+				} else {
+					members = append(members, member)
+				}
+			}
+			elems = append(elems, element{kind: categoryElement, members: members})
+			runes = runes[end+1:]
+
+		default:
+			if members, ok := rs.Categories[string(r)]; ok {
+				elems = append(elems, element{kind: categoryElement, members: members})
+			} else {
+				elems = append(elems, element{kind: literalElement, text: string(r)})
+			}
+			runes = runes[1:]
+		}
+	}
+
+	if inGroup {
+		return nil, nil, fmt.Errorf("unterminated `(`")
+	}
+	return elems, runes, nil
+}
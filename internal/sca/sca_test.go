@@ -0,0 +1,145 @@
+package sca
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		word  string
+		want  string
+	}{
+		{"plain change", "a > e", "banana", "benene"},
+		{"following environment", "a > e / _i", "kaita", "keita"},
+		{"preceding environment", "a > e / i_", "kiata", "kieta"},
+		{"both sides", "a > e / t_t", "tatat", "tetet"},
+		{"categories must come first", "p, t, k > b, d, g / V_V\nV=aeiou", "apatako", "apatako"},
+		{"category environment", "V=aeiou\np, t, k > b, d, g / V_V", "apatako", "abadago"},
+		{"shared replacement", "V=aeiou\np, t, k > h / V_V", "apatako", "ahahaho"},
+		{"category to category", "V=aeiou\n[ptk] > [bdg] / V_V", "apatako", "abadago"},
+		{"multi-character members", "F=th sh\nF > h / #_", "thin", "hin"},
+		{"longest alternative wins", "[t th] > [d ð]", "thet", "ðed"},
+		{"deletion at word end", "V=aeiou\nV > / _#", "lasse", "lass"},
+		{"word-initial boundary", "h > / #_", "haha", "aha"},
+		{"insertion", "C=ptks\n∅ > e / #_sC", "spata", "espata"},
+		{"optional environment element", "a > e / _(n)t", "atant", "etent"},
+		{"exception", "h > / _ // a_", "ohaho", "oaho"},
+		{"simultaneous application", "a > b / a_", "aaa", "abb"},
+		{"ordered rules", "a > b\nb > c", "ab", "cc"},
+		{"nonce rule applies to listed words", "o > u / _n {kon tor}", "kon", "kun"},
+		{"nonce rule skips other words", "o > u / _n {kon tor}", "son", "son"},
+		{"comments", "; lenition\na > e ; fronting", "ka", "ke"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs, err := Parse(tt.rules)
+			if err != nil {
+				t.Fatalf("Parse(%q): %s", tt.rules, err)
+			}
+			if got := rs.Apply(tt.word, 0).Output; got != tt.want {
+				t.Errorf("%q on %q: got %q, want %q", tt.rules, tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTrace(t *testing.T) {
+	rs, err := Parse("a > e\nx > y\ne > i")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := rs.Apply("ka", 0)
+	want := []Step{
+		{Rule: "a > e", Before: "ka", After: "ke"},
+		{Rule: "e > i", Before: "ke", After: "ki"},
+	}
+	if len(result.Trace) != len(want) {
+		t.Fatalf("got trace %v, want %v", result.Trace, want)
+	}
+	for i := range want {
+		if result.Trace[i] != want[i] {
+			t.Errorf("step %d: got %v, want %v", i, result.Trace[i], want[i])
+		}
+	}
+}
+
+func TestOptionalRules(t *testing.T) {
+	never, err := Parse("?0 a > e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	always, err := Parse("?100 a > e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	half, err := Parse("? a > e")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := 0
+	for _, word := range strings.Fields("ka ta pa sa ma na la ra va za ga da ba fa ha ja") {
+		if got := never.Apply(word, 1).Output; got != word {
+			t.Errorf("?0 rule changed %q to %q", word, got)
+		}
+		if got := always.Apply(word, 1).Output; got == word {
+			t.Errorf("?100 rule left %q unchanged", word)
+		}
+		// The same seed must always give the same result.
+		first := half.Apply(word, 7).Output
+		if again := half.Apply(word, 7).Output; again != first {
+			t.Errorf("seed 7 gave %q then %q for %q", first, again, word)
+		}
+		if first != word {
+			changed++
+		}
+	}
+	if changed == 0 || changed == 16 {
+		t.Errorf("a 50%% rule changed %d of 16 words", changed)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{"no arrow", "a e"},
+		{"no underscore", "a > e / i"},
+		{"two underscores", "a > e / _i_"},
+		{"mismatched alternatives", "a, b, c > d, e"},
+		{"mismatched categories", "[ptk] > [bd]"},
+		{"boundary in target", "# > a"},
+		{"unterminated group", "a > e / _(n"},
+		{"unmatched parenthesis", "a > e / _n)"},
+		{"unterminated category", "a > e / _[ie"},
+		{"unterminated nonce list", "a > e {kat"},
+		{"two nonce lists", "a > b / _ {x} {y}"},
+		{"nonce list before environment", "a > b {x} / _y"},
+		{"chance above 100", "?150 a > e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.rules); err == nil {
+				t.Errorf("Parse(%q) succeeded, want an error", tt.rules)
+			}
+		})
+	}
+}
+
+func TestParseErrorLine(t *testing.T) {
+	_, err := Parse("V=aeiou\n\na > e / V_\nb e")
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("got %v, want a *ParseError", err)
+	}
+	if parseErr.Line != 4 {
+		t.Errorf("got line %d, want 4", parseErr.Line)
+	}
+}
@@ -0,0 +1,329 @@
+// Package sfm reads and writes Standard Format Marker files, the plain text
+// format of Toolbox and Shoebox dictionaries. Each line starts with a marker
+// such as `\lx`, followed by its value, and lines without a marker continue
+// the value above them. Records begin at the record marker, and any lines
+// before the first record are the file's header.
+package sfm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+type Field struct {
+	Marker string
+	Value  string
+	Line   int
+}
+
+// The fields of a record, in order, starting with its record marker.
+type Record struct {
+	Line   int
+	Fields []Field
+}
+
+// Splits an SFM file into records, starting a record at every field with the
+// record marker. Markers are given without their backslash.
+func Parse(r io.Reader, recordMarker string) ([]Record, error) {
+	records := []Record{}
+	var field *Field
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+
+		if !strings.HasPrefix(text, `\`) {
+			if field != nil && strings.TrimSpace(text) != "" {
+				field.Value = strings.TrimSpace(field.Value + " " + strings.TrimSpace(text))
+			}
+			continue
+		}
+
+		marker, value, _ := strings.Cut(text[1:], " ")
+		if marker == recordMarker {
+			records = append(records, Record{Line: line})
+		}
+		if len(records) == 0 {
+			// Header fields, such as `\_sh`, describe the file rather than
+			// any record.
+			field = nil
+			continue
+		}
+
+		record := &records[len(records)-1]
+		record.Fields = append(record.Fields, Field{
+			Marker: marker,
+			Value:  strings.TrimSpace(value),
+			Line:   line,
+		})
+		field = &record.Fields[len(record.Fields)-1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// Which markers hold which parts of an entry. Markers are given without
+// their backslash.
+type Mapping struct {
+	Record             string `json:"record"`
+	PartOfSpeech       string `json:"part_of_speech"`
+	Sense              string `json:"sense"`
+	Gloss              string `json:"gloss"`
+	Definition         string `json:"definition"`
+	Example            string `json:"example"`
+	ExampleTranslation string `json:"example_translation"`
+}
+
+// The markers of Multi-Dictionary Formatter, the dictionary marker set that
+// Toolbox ships with.
+var MDF = Mapping{
+	Record:             "lx",
+	PartOfSpeech:       "ps",
+	Sense:              "sn",
+	Gloss:              "ge",
+	Definition:         "de",
+	Example:            "xv",
+	ExampleTranslation: "xe",
+}
+
+// Fills in the markers left empty with those of MDF.
+func (m Mapping) WithDefaults() Mapping {
+	defaults := func(marker *string, fallback string) {
+		*marker = strings.TrimPrefix(strings.TrimSpace(*marker), `\`)
+		if *marker == "" {
+			*marker = fallback
+		}
+	}
+	defaults(&m.Record, MDF.Record)
+	defaults(&m.PartOfSpeech, MDF.PartOfSpeech)
+	defaults(&m.Sense, MDF.Sense)
+	defaults(&m.Gloss, MDF.Gloss)
+	defaults(&m.Definition, MDF.Definition)
+	defaults(&m.Example, MDF.Example)
+	defaults(&m.ExampleTranslation, MDF.ExampleTranslation)
+	return m
+}
+
+// Checks that no marker is given two meanings, or contains whitespace.
+func (m Mapping) Check() error {
+	markers := []string{
+		m.Record, m.PartOfSpeech, m.Sense, m.Gloss,
+		m.Definition, m.Example, m.ExampleTranslation,
+	}
+	for i, marker := range markers {
+		if strings.ContainsAny(marker, " \t\\") {
+			return fmt.Errorf(`marker %q may not contain whitespace or "\"`, marker)
+		}
+		if slices.Contains(markers[:i], marker) {
+			return fmt.Errorf(`marker \%s is mapped more than once`, marker)
+		}
+	}
+	return nil
+}
+
+// A dictionary entry: a headword and its senses.
+type Entry struct {
+	Line   int
+	Word   string
+	Senses []Sense
+}
+
+// A sense is worded by its definition or, failing that, its gloss.
+type Sense struct {
+	PartOfSpeech string
+	Gloss        string
+	Definition   string
+	Examples     []Example
+}
+
+func (s Sense) Content() string {
+	if s.Definition != "" {
+		return s.Definition
+	}
+	return s.Gloss
+}
+
+type Example struct {
+	Source      string
+	Translation string
+}
+
+// A record that could not be read as an entry, and why.
+type Failure struct {
+	Line   int    `json:"line"`
+	Record string `json:"record"`
+	Reason string `json:"reason"`
+}
+
+// A marker the mapping does not cover, and how many times it occurred.
+type UnmappedMarker struct {
+	Marker string `json:"marker"`
+	Count  int    `json:"count"`
+}
+
+// Reads records as dictionary entries. A part of speech applies to every
+// sense after it, until the next, and to the current sense if nothing has
+// been read into it yet. A sense begins at each sense marker, or at
+// a gloss or definition when the current sense already has one. Records
+// without a headword, or with a sense that has no gloss, definition or part
+// of speech, fail as a whole. Glosses of senses that also have a definition
+// have nowhere to go, and are counted as unmapped.
+func (m Mapping) Entries(records []Record) ([]Entry, []Failure, []UnmappedMarker) {
+	entries := []Entry{}
+	failures := []Failure{}
+	unmapped := map[string]int{}
+
+	for _, record := range records {
+		entry, err := m.readEntry(record, unmapped)
+		if err != nil {
+			failures = append(failures, Failure{
+				Line:   record.Line,
+				Record: record.Fields[0].Value,
+				Reason: err.Error(),
+			})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	markers := []UnmappedMarker{}
+	for marker, count := range unmapped {
+		markers = append(markers, UnmappedMarker{Marker: `\` + marker, Count: count})
+	}
+	slices.SortFunc(markers, func(a, b UnmappedMarker) int {
+		return strings.Compare(a.Marker, b.Marker)
+	})
+
+	return entries, failures, markers
+}
+
+func (m Mapping) readEntry(record Record, unmapped map[string]int) (Entry, error) {
+	entry := Entry{Line: record.Line, Word: record.Fields[0].Value}
+	if entry.Word == "" {
+		return Entry{}, fmt.Errorf(`\%s has no value`, m.Record)
+	}
+
+	partOfSpeech := ""
+	// The sense being read, if any has begun.
+	var sense *Sense
+	begin := func() {
+		entry.Senses = append(entry.Senses, Sense{PartOfSpeech: partOfSpeech})
+		sense = &entry.Senses[len(entry.Senses)-1]
+	}
+
+	for _, field := range record.Fields[1:] {
+		switch field.Marker {
+		case m.PartOfSpeech:
+			partOfSpeech = field.Value
+			// MDF numbers a sense before giving its part of speech, so a
+			// sense that is still empty takes it. Otherwise the next gloss,
+			// definition or example begins a new sense.
+			if sense != nil && sense.Gloss == "" && sense.Definition == "" && len(sense.Examples) == 0 {
+				sense.PartOfSpeech = partOfSpeech
+			} else {
+				sense = nil
+			}
+		case m.Sense:
+			begin()
+		case m.Gloss:
+			if sense == nil || sense.Gloss != "" {
+				begin()
+			}
+			sense.Gloss = field.Value
+		case m.Definition:
+			if sense == nil || sense.Definition != "" {
+				begin()
+			}
+			sense.Definition = field.Value
+		case m.Example:
+			if sense == nil {
+				begin()
+			}
+			sense.Examples = append(sense.Examples, Example{Source: field.Value})
+		case m.ExampleTranslation:
+			if sense == nil || len(sense.Examples) == 0 {
+				return Entry{}, fmt.Errorf(`line %d: \%s comes before any \%s`, field.Line, m.ExampleTranslation, m.Example)
+			}
+			sense.Examples[len(sense.Examples)-1].Translation = field.Value
+		default:
+			unmapped[field.Marker]++
+		}
+	}
+
+	if len(entry.Senses) == 0 {
+		return Entry{}, fmt.Errorf(`no \%s or \%s`, m.Gloss, m.Definition)
+	}
+	for i, s := range entry.Senses {
+		if s.Definition != "" && s.Gloss != "" {
+			unmapped[m.Gloss]++
+		}
+		if s.Content() == "" {
+			return Entry{}, fmt.Errorf(`sense %d has no \%s or \%s`, i+1, m.Gloss, m.Definition)
+		}
+		if s.PartOfSpeech == "" {
+			return Entry{}, fmt.Errorf(`sense %d has no \%s`, i+1, m.PartOfSpeech)
+		}
+	}
+
+	return entry, nil
+}
+
+// Writes entries as SFM records, under an MDF header. Consecutive senses
+// with the same part of speech share one part of speech marker, and are
+// numbered when there is more than one.
+func (m Mapping) Write(w io.Writer, entries []Entry) error {
+	b := bufio.NewWriter(w)
+	writeField := func(marker, value string) {
+		// Values span a single line, so that no line of a value can be
+		// mistaken for a marker.
+		value = strings.Join(strings.Fields(value), " ")
+		fmt.Fprintf(b, "\\%s %s\n", marker, value)
+	}
+
+	b.WriteString("\\_sh v3.0  400  MDF 4.0\n")
+	for _, entry := range entries {
+		b.WriteString("\n")
+		writeField(m.Record, entry.Word)
+
+		for i := 0; i < len(entry.Senses); {
+			group := i + 1
+			for group < len(entry.Senses) && entry.Senses[group].PartOfSpeech == entry.Senses[i].PartOfSpeech {
+				group++
+			}
+
+			writeField(m.PartOfSpeech, entry.Senses[i].PartOfSpeech)
+			for n, sense := range entry.Senses[i:group] {
+				if group-i > 1 {
+					writeField(m.Sense, fmt.Sprint(n+1))
+				}
+				if sense.Gloss != "" {
+					writeField(m.Gloss, sense.Gloss)
+				}
+				if sense.Definition != "" {
+					writeField(m.Definition, sense.Definition)
+				}
+				for _, example := range sense.Examples {
+					writeField(m.Example, example.Source)
+					if example.Translation != "" {
+						writeField(m.ExampleTranslation, example.Translation)
+					}
+				}
+			}
+			i = group
+		}
+	}
+
+	return b.Flush()
+}
@@ -0,0 +1,127 @@
+package sfm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func readEntries(t *testing.T, text string) ([]Entry, []Failure) {
+	t.Helper()
+	records, err := Parse(strings.NewReader(text), MDF.Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, failures, _ := MDF.Entries(records)
+	return entries, failures
+}
+
+func TestPartOfSpeechPlacement(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Sense
+	}{
+		{
+			"sense number before part of speech",
+			`\lx foo
+\sn 1
+\ps n
+\ge dog
+\sn 2
+\ps v
+\ge bark`,
+			[]Sense{{PartOfSpeech: "n", Gloss: "dog"}, {PartOfSpeech: "v", Gloss: "bark"}},
+		},
+		{
+			"part of speech before sense numbers",
+			`\lx foo
+\ps n
+\sn 1
+\ge dog
+\sn 2
+\ge hound`,
+			[]Sense{{PartOfSpeech: "n", Gloss: "dog"}, {PartOfSpeech: "n", Gloss: "hound"}},
+		},
+		{
+			"part of speech between unnumbered senses",
+			`\lx foo
+\ps n
+\ge dog
+\ps v
+\ge bark`,
+			[]Sense{{PartOfSpeech: "n", Gloss: "dog"}, {PartOfSpeech: "v", Gloss: "bark"}},
+		},
+		{
+			"gloss and definition of one sense",
+			`\lx foo
+\ps n
+\ge dog
+\de a domesticated canine`,
+			[]Sense{{PartOfSpeech: "n", Gloss: "dog", Definition: "a domesticated canine"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, failures := readEntries(t, tt.text)
+			if len(failures) > 0 {
+				t.Fatalf("got failures %+v", failures)
+			}
+			if len(entries) != 1 || len(entries[0].Senses) != len(tt.want) {
+				t.Fatalf("got %+v, want one entry with senses %+v", entries, tt.want)
+			}
+			for i, want := range tt.want {
+				got := entries[0].Senses[i]
+				if got.PartOfSpeech != want.PartOfSpeech || got.Gloss != want.Gloss || got.Definition != want.Definition {
+					t.Errorf("sense %d: got %+v, want %+v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestIncompleteRecordsFail(t *testing.T) {
+	_, failures := readEntries(t, `\lx foo
+\ps n
+
+\lx bar
+\ge dog
+
+\lx baz
+\ps n
+\ge dog
+\sn 2`)
+	if len(failures) != 3 {
+		t.Fatalf("got failures %+v, want one per record", failures)
+	}
+}
+
+func TestWriteReadsBack(t *testing.T) {
+	entries := []Entry{{
+		Word: "foo",
+		Senses: []Sense{
+			{PartOfSpeech: "n", Definition: "dog", Examples: []Example{{Source: "foo foo", Translation: "dogs"}}},
+			{PartOfSpeech: "n", Definition: "hound"},
+			{PartOfSpeech: "v", Definition: "bark"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := MDF.Write(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	read, failures := readEntries(t, buf.String())
+	if len(failures) > 0 {
+		t.Fatalf("got failures %+v reading\n%s", failures, buf.String())
+	}
+	if len(read) != 1 || len(read[0].Senses) != 3 {
+		t.Fatalf("got %+v, want one entry with three senses", read)
+	}
+	for i, want := range entries[0].Senses {
+		got := read[0].Senses[i]
+		if got.PartOfSpeech != want.PartOfSpeech || got.Definition != want.Definition || len(got.Examples) != len(want.Examples) {
+			t.Errorf("sense %d: got %+v, want %+v", i+1, got, want)
+		}
+	}
+}
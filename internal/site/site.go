@@ -0,0 +1,265 @@
+// Package site writes dictionaries as a static website, which can be served
+// from any plain file host. Every language gets an index, a page for each
+// initial letter and a page for each word, along with a JSON search index
+// that the language's index searches in the browser.
+//
+// Pages live at directory paths, so that their permalinks read as
+// /<language>/words/<headword>/. A permalink is derived from its own language
+// or word alone, never from the others published alongside it, so it stays
+// the same from one generation to the next, whichever languages are
+// published.
+package site
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"vastestsea/internal/printing"
+)
+
+// A word to publish. Its ID tells its permalink apart from those of words
+// whose headwords slug alike.
+type Entry struct {
+	printing.Entry
+	ID string
+}
+
+// A language to publish, with all of its words.
+type Language struct {
+	Name    string
+	ID      string
+	Entries []Entry
+}
+
+// Turns text into a path segment: lower case letters, marks and digits, with
+// a hyphen for each run of anything else. Text with none of those is spelled
+// out by its code points, so that it still has a segment of its own.
+func Slug(text string) string {
+	slug := strings.Builder{}
+	separate := false
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) {
+			separate = true
+			continue
+		}
+		if separate && slug.Len() > 0 {
+			slug.WriteByte('-')
+		}
+		separate = false
+		slug.WriteRune(r)
+	}
+	if slug.Len() > 0 {
+		return slug.String()
+	}
+
+	for _, r := range text {
+		fmt.Fprintf(&slug, "u%04x", r)
+	}
+	return slug.String()
+}
+
+// The path segment of a language or word. Names that are already their own
+// slug, such as "mellon", keep it. Any other name could slug like one of
+// those, or like another, so the start of its ID is appended, as in
+// "mellon-1b4e28ba" for "Mellon".
+func permalink(name, id string) string {
+	slug := Slug(name)
+	if slug == name {
+		return slug
+	}
+	return slug + "-" + id[:min(len(id), 8)]
+}
+
+// The path segment of a letter page.
+func letterSlug(letter string) string {
+	if letter == "#" {
+		return "symbols"
+	}
+	return Slug(letter)
+}
+
+// A word in the search index. URL is relative to the language's index.
+type SearchEntry struct {
+	Headword      string `json:"headword"`
+	FontFormatted string `json:"font_formatted,omitempty"`
+	Summary       string `json:"summary"`
+	URL           string `json:"url"`
+}
+
+// Writes the site for languages into dir, replacing whatever dir held. The
+// site is written beside dir and only swapped in once complete, so a failed
+// run leaves the previous site untouched, and pages no longer generated do
+// not linger.
+func Write(dir string, languages []Language) error {
+	dir = filepath.Clean(dir)
+	staging, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-")
+	if err != nil {
+		return err
+	}
+	// Only left to remove if the swap never happened.
+	defer os.RemoveAll(staging)
+
+	if err := os.Chmod(staging, 0o755); err != nil {
+		return err
+	}
+	if err := writeSite(staging, languages); err != nil {
+		return err
+	}
+	return replaceDir(staging, dir)
+}
+
+// Moves the directory from into place at to, removing what was there.
+func replaceDir(from, to string) error {
+	previous := ""
+	if _, err := os.Stat(to); err == nil {
+		previous = from + ".old"
+		if err := os.Rename(to, previous); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if err := os.Rename(from, to); err != nil {
+		if previous != "" {
+			os.Rename(previous, to)
+		}
+		return err
+	}
+	if previous != "" {
+		return os.RemoveAll(previous)
+	}
+	return nil
+}
+
+func writeSite(dir string, languages []Language) error {
+	index := indexPage{page: page{Title: "Dictionaries", Root: "./"}}
+	for _, language := range languages {
+		slug := permalink(language.Name, language.ID)
+		if err := writeLanguage(filepath.Join(dir, slug), language); err != nil {
+			return fmt.Errorf("%s: %w", language.Name, err)
+		}
+		index.Languages = append(index.Languages, languageLink{
+			Name:  language.Name,
+			URL:   slug + "/",
+			Words: len(language.Entries),
+		})
+	}
+	slices.SortFunc(index.Languages, func(a, b languageLink) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	if err := writeFile(filepath.Join(dir, "style.css"), []byte(stylesheet)); err != nil {
+		return err
+	}
+	return writePage(filepath.Join(dir, "index.html"), "index", index)
+}
+
+func writeLanguage(dir string, language Language) error {
+	entries := []printing.Entry{}
+	wordSlugs := map[string]string{}
+	for _, entry := range language.Entries {
+		entries = append(entries, entry.Entry)
+		wordSlugs[entry.Headword] = permalink(entry.Headword, entry.ID)
+	}
+	dictionary := printing.New(language.Name, entries)
+
+	languageIndex := languagePage{page: page{Title: language.Name, Root: "../"}}
+	for _, letter := range dictionary.Letters {
+		languageIndex.Letters = append(languageIndex.Letters, letterLink{
+			Letter: letter.Letter,
+			URL:    "letters/" + letterSlug(letter.Letter) + "/",
+			Words:  len(letter.Entries),
+		})
+	}
+
+	// Letter pages link to each other from two levels down.
+	siblings := []letterLink{}
+	for _, link := range languageIndex.Letters {
+		link.URL = "../../" + link.URL
+		siblings = append(siblings, link)
+	}
+
+	search := []SearchEntry{}
+	for _, letter := range dictionary.Letters {
+		letterIndex := letterPage{
+			page:     page{Title: language.Name + ": " + letter.Letter, Root: "../../../"},
+			Language: language.Name,
+			Letter:   letter.Letter,
+			Letters:  siblings,
+		}
+		for _, article := range letter.Entries {
+			slug := wordSlugs[article.Headword]
+			summary := summarize(article)
+			letterIndex.Words = append(letterIndex.Words, wordLink{
+				Headword:      article.Headword,
+				FontFormatted: article.FontFormatted,
+				Summary:       summary,
+				URL:           "../../words/" + slug + "/",
+			})
+			search = append(search, SearchEntry{
+				Headword:      article.Headword,
+				FontFormatted: article.FontFormatted,
+				Summary:       summary,
+				URL:           "words/" + slug + "/",
+			})
+
+			wordPage := wordPage{
+				page:      page{Title: article.Headword + " – " + language.Name, Root: "../../../"},
+				Language:  language.Name,
+				Letter:    letter.Letter,
+				LetterURL: "../../letters/" + letterSlug(letter.Letter) + "/",
+				Article:   article,
+			}
+			if err := writePage(filepath.Join(dir, "words", slug, "index.html"), "word", wordPage); err != nil {
+				return err
+			}
+		}
+
+		if err := writePage(filepath.Join(dir, "letters", letterSlug(letter.Letter), "index.html"), "letter", letterIndex); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(search)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, "search.json"), data); err != nil {
+		return err
+	}
+	return writePage(filepath.Join(dir, "index.html"), "language", languageIndex)
+}
+
+// A line summing up a word's senses, for lists and search results.
+func summarize(article printing.Article) string {
+	senses := []string{}
+	for _, group := range article.Groups {
+		for _, sense := range group.Senses {
+			senses = append(senses, sense.Definition)
+		}
+	}
+	return strings.Join(senses, "; ")
+}
+
+func writePage(path string, name string, data any) error {
+	buf := bytes.Buffer{}
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+	return writeFile(path, buf.Bytes())
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
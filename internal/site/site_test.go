@@ -0,0 +1,114 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"vastestsea/internal/printing"
+)
+
+func entry(headword, id string) Entry {
+	return Entry{
+		Entry: printing.Entry{
+			Headword: headword,
+			Senses:   []printing.Sense{{PartOfSpeech: "noun", Definition: "friend"}},
+		},
+		ID: id,
+	}
+}
+
+func TestPermalinks(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{"mellon", "1b4e28ba-2fa1", "mellon"},
+		{"ëa", "1b4e28ba-2fa1", "ëa"},
+		{"Mellon", "1b4e28ba-2fa1", "mellon-1b4e28ba"},
+		{"pedo mellon", "7c9e6679-7425", "pedo-mellon-7c9e6679"},
+		{"+", "7c9e6679-7425", "u002b-7c9e6679"},
+	}
+
+	for _, tt := range tests {
+		if got := permalink(tt.name, tt.id); got != tt.want {
+			t.Errorf("permalink(%q, %q) = %q, want %q", tt.name, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestWriteRemovesStalePages(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "site")
+
+	first := []Language{{Name: "quenya", ID: "q", Entries: []Entry{entry("mellon", "a"), entry("meldo", "b")}}}
+	if err := Write(dir, first); err != nil {
+		t.Fatal(err)
+	}
+	meldo := filepath.Join(dir, "quenya", "words", "meldo", "index.html")
+	if _, err := os.Stat(meldo); err != nil {
+		t.Fatalf("meldo was not written: %s", err)
+	}
+
+	second := []Language{{Name: "quenya", ID: "q", Entries: []Entry{entry("mellon", "a")}}}
+	if err := Write(dir, second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(meldo); !os.IsNotExist(err) {
+		t.Errorf("the page of a removed word was left behind")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "quenya", "words", "mellon", "index.html")); err != nil {
+		t.Errorf("mellon was not rewritten: %s", err)
+	}
+
+	// Nothing is left beside the site.
+	siblings, err := os.ReadDir(filepath.Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(siblings) != 1 {
+		t.Errorf("got %d entries beside the site, want only the site", len(siblings))
+	}
+}
+
+func TestAffixesAreFiledUnderTheirLetter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "site")
+	entries := []Entry{entry("apple", "a"), entry("-ka", "b"), entry("kiri", "c"), entry("bob", "d")}
+	if err := Write(dir, []Language{{Name: "quenya", ID: "q", Entries: entries}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "quenya", "search.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	search := []SearchEntry{}
+	if err := json.Unmarshal(data, &search); err != nil {
+		t.Fatal(err)
+	}
+	headwords := []string{}
+	for _, s := range search {
+		headwords = append(headwords, s.Headword)
+	}
+	if want := []string{"apple", "bob", "-ka", "kiri"}; !slices.Equal(headwords, want) {
+		t.Errorf("got headwords in order %q, want %q", headwords, want)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "quenya", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, k := strings.Index(string(index), "letters/a/"), strings.Index(string(index), "letters/k/")
+	if a < 0 || k < 0 || k < a {
+		t.Errorf("the letter index does not link A before K:\n%s", index)
+	}
+	letter, err := os.ReadFile(filepath.Join(dir, "quenya", "letters", "k", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(letter), "-ka") {
+		t.Errorf("-ka is missing from the K page")
+	}
+}
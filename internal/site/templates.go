@@ -0,0 +1,150 @@
+package site
+
+import (
+	"html/template"
+	"vastestsea/internal/printing"
+)
+
+// What every page has. Root leads from the page back to the top of the site,
+// so that links work wherever the site is hosted.
+type page struct {
+	Title string
+	Root  string
+}
+
+type languageLink struct {
+	Name  string
+	URL   string
+	Words int
+}
+
+type indexPage struct {
+	page
+	Languages []languageLink
+}
+
+type letterLink struct {
+	Letter string
+	URL    string
+	Words  int
+}
+
+type languagePage struct {
+	page
+	Letters []letterLink
+}
+
+type wordLink struct {
+	Headword      string
+	FontFormatted string
+	Summary       string
+	URL           string
+}
+
+type letterPage struct {
+	page
+	Language string
+	Letter   string
+	Letters  []letterLink
+	Words    []wordLink
+}
+
+type wordPage struct {
+	page
+	Language  string
+	Letter    string
+	LetterURL string
+	printing.Article
+}
+
+const stylesheet = `body { font-family: Georgia, serif; margin: 2em auto; max-width: 45em; padding: 0 1em; line-height: 1.5; }
+header { border-bottom: 1px solid #999; margin-bottom: 1.5em; }
+nav.letters a { margin-right: 0.5em; }
+ul.words { list-style: none; padding: 0; }
+ul.words li { margin-bottom: 0.25em; }
+.part-of-speech, .example-source { font-style: italic; }
+.summary { color: #555; }
+ol.senses { margin-top: 0; }
+#search { width: 100%; font-size: 1em; padding: 0.25em; }
+`
+
+var templates = template.Must(template.New("site").Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header><a href="{{.Root}}">Dictionaries</a></header>
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+
+{{define "letters"}}<nav class="letters">{{range .}}<a href="{{.URL}}">{{.Letter}}</a>{{end}}</nav>{{end}}
+
+{{define "index"}}{{template "header" .}}<h1>{{.Title}}</h1>
+<ul class="languages">
+{{range .Languages}}<li><a href="{{.URL}}">{{.Name}}</a> ({{.Words}} words)</li>
+{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "language"}}{{template "header" .}}<h1>{{.Title}}</h1>
+{{template "letters" .Letters}}
+<p><input id="search" type="search" placeholder="Search words and definitions" autocomplete="off"></p>
+<ul class="words" id="results"></ul>
+<script>
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var index = null;
+  function show() {
+    var query = input.value.trim().toLowerCase();
+    results.textContent = "";
+    if (!query || !index) return;
+    index.filter(function (entry) {
+      return entry.headword.toLowerCase().indexOf(query) >= 0 ||
+        entry.summary.toLowerCase().indexOf(query) >= 0;
+    }).slice(0, 50).forEach(function (entry) {
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = entry.url;
+      link.textContent = entry.headword;
+      item.appendChild(link);
+      item.appendChild(document.createTextNode(" " + entry.summary));
+      results.appendChild(item);
+    });
+  }
+  fetch("search.json").then(function (response) {
+    return response.json();
+  }).then(function (entries) {
+    index = entries;
+    show();
+  });
+  input.addEventListener("input", show);
+})();
+</script>
+{{template "footer"}}{{end}}
+
+{{define "letter"}}{{template "header" .}}<h1><a href="../../">{{.Language}}</a>: {{.Letter}}</h1>
+{{template "letters" .Letters}}
+<ul class="words">
+{{range .Words}}<li><a href="{{.URL}}">{{.Headword}}</a>{{if .FontFormatted}} <span class="font-formatted">{{.FontFormatted}}</span>{{end}} <span class="summary">{{.Summary}}</span></li>
+{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "examples"}}{{if .}}<ul class="examples">{{range .}}<li><span class="example-source">{{.Source}}</span>{{if .Translation}} ‘{{.Translation}}’{{end}}</li>{{end}}</ul>{{end}}{{end}}
+
+{{define "word"}}{{template "header" .}}<p><a href="../../">{{.Language}}</a> › <a href="{{.LetterURL}}">{{.Letter}}</a></p>
+<h1>{{.Headword}}{{if .FontFormatted}} <span class="font-formatted">{{.FontFormatted}}</span>{{end}}</h1>
+{{range .Groups}}{{if .PartOfSpeech}}<h2 class="part-of-speech">{{.PartOfSpeech}}</h2>{{end}}
+<ol class="senses">
+{{range .Senses}}<li>{{.Definition}}{{template "examples" .Examples}}</li>
+{{end}}</ol>
+{{end}}{{template "examples" .Examples}}
+{{template "footer"}}{{end}}
+`))
@@ -0,0 +1,611 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// In-memory Store, intended for tests, demos and local development without a
+// database. It enforces the same unique constraints and cascading deletes as
+// the schema in sql/schema.
+type MemoryStore struct {
+	mu sync.RWMutex
+	memoryTables
+	// Set by SetSimilarityThreshold, and like the Postgres setting only lasts
+	// as long as the transaction it is set in.
+	similarityThreshold float32
+}
+
+// The rows held by a MemoryStore, one map per table.
+type memoryTables struct {
+	languages   map[uuid.UUID]database.Language
+	words       map[uuid.UUID]database.Word
+	definitions map[uuid.UUID]database.Definition
+
+	wordRelations map[uuid.UUID]database.WordRelation
+	translations  map[uuid.UUID]database.Translation
+	phonologies   map[uuid.UUID]database.Phonology
+
+	inflectionClasses     map[uuid.UUID]database.InflectionClass
+	inflectionRules       map[uuid.UUID]database.InflectionRule
+	wordInflectionClasses map[wordClassKey]database.WordInflectionClass
+	inflectionOverrides   map[uuid.UUID]database.InflectionOverride
+	lemmaRules            map[uuid.UUID]database.LemmaRule
+
+	examples map[uuid.UUID]database.Example
+	texts    map[uuid.UUID]database.Text
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		similarityThreshold: defaultSimilarityThreshold,
+		memoryTables: memoryTables{
+			languages:   map[uuid.UUID]database.Language{},
+			words:       map[uuid.UUID]database.Word{},
+			definitions: map[uuid.UUID]database.Definition{},
+
+			wordRelations: map[uuid.UUID]database.WordRelation{},
+			translations:  map[uuid.UUID]database.Translation{},
+			phonologies:   map[uuid.UUID]database.Phonology{},
+
+			inflectionClasses:     map[uuid.UUID]database.InflectionClass{},
+			inflectionRules:       map[uuid.UUID]database.InflectionRule{},
+			wordInflectionClasses: map[wordClassKey]database.WordInflectionClass{},
+			inflectionOverrides:   map[uuid.UUID]database.InflectionOverride{},
+			lemmaRules:            map[uuid.UUID]database.LemmaRule{},
+
+			examples: map[uuid.UUID]database.Example{},
+			texts:    map[uuid.UUID]database.Text{},
+		},
+	}
+}
+
+// Copies every table, so that a transaction can work on the copy.
+func (t memoryTables) clone() memoryTables {
+	return memoryTables{
+		languages:   maps.Clone(t.languages),
+		words:       maps.Clone(t.words),
+		definitions: maps.Clone(t.definitions),
+
+		wordRelations: maps.Clone(t.wordRelations),
+		translations:  maps.Clone(t.translations),
+		phonologies:   maps.Clone(t.phonologies),
+
+		inflectionClasses:     maps.Clone(t.inflectionClasses),
+		inflectionRules:       maps.Clone(t.inflectionRules),
+		wordInflectionClasses: maps.Clone(t.wordInflectionClasses),
+		inflectionOverrides:   maps.Clone(t.inflectionOverrides),
+		lemmaRules:            maps.Clone(t.lemmaRules),
+
+		examples: maps.Clone(t.examples),
+		texts:    maps.Clone(t.texts),
+	}
+}
+
+// Runs fn against a copy of the store, and only keeps its changes if fn
+// succeeds. The store is locked for the duration, so transactions are
+// serializable with respect to each other and to non-transactional calls.
+func (s *MemoryStore) RunInTx(ctx context.Context, fn func(Store) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &MemoryStore{memoryTables: s.memoryTables.clone(), similarityThreshold: defaultSimilarityThreshold}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.memoryTables = tx.memoryTables
+	return nil
+}
+
+// Runs fn against a copy of the store taken when it is called. The store is
+// only locked while it is copied, and writes to the copy are discarded.
+func (s *MemoryStore) RunInSnapshot(ctx context.Context, fn func(Store) error) error {
+	s.mu.RLock()
+	snapshot := &MemoryStore{memoryTables: s.memoryTables.clone(), similarityThreshold: defaultSimilarityThreshold}
+	s.mu.RUnlock()
+
+	return fn(snapshot)
+}
+
+func duplicateKeyError(constraint string) error {
+	return fmt.Errorf("%w %q", ErrDuplicateKey, constraint)
+}
+
+// Mirrors NOW() on a TIMESTAMP column, which carries no time zone.
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// Returns the values of a map ordered by creation time, so that listings are
+// stable between calls.
+func sortedByCreation[T any](m map[uuid.UUID]T, createdAt func(T) time.Time) []T {
+	items := make([]T, 0, len(m))
+	for _, v := range m {
+		items = append(items, v)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return createdAt(items[i]).Before(createdAt(items[j]))
+	})
+	return items
+}
+
+/*
+ * Languages
+ */
+
+func (s *MemoryStore) languageNameTaken(name string, except uuid.UUID) bool {
+	for _, l := range s.languages {
+		if l.Name == name && l.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) CreateLanguage(ctx context.Context, name string) (database.Language, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.languageNameTaken(name, uuid.Nil) {
+		return database.Language{}, duplicateKeyError("languages_name_key")
+	}
+
+	t := now()
+	language := database.Language{
+		ID:        uuid.New(),
+		CreatedAt: t,
+		UpdatedAt: t,
+		Name:      name,
+	}
+	s.languages[language.ID] = language
+
+	return language, nil
+}
+
+func (s *MemoryStore) GetLanguages(ctx context.Context) ([]database.Language, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedByCreation(s.languages, func(l database.Language) time.Time { return l.CreatedAt }), nil
+}
+
+func (s *MemoryStore) getLanguage(name string) (database.Language, bool) {
+	for _, l := range sortedByCreation(s.languages, func(l database.Language) time.Time { return l.CreatedAt }) {
+		if strings.ToLower(l.Name) == name {
+			return l, true
+		}
+	}
+	return database.Language{}, false
+}
+
+func (s *MemoryStore) GetLanguage(ctx context.Context, name string) (database.Language, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	language, ok := s.getLanguage(name)
+	if !ok {
+		return database.Language{}, sql.ErrNoRows
+	}
+	return language, nil
+}
+
+func (s *MemoryStore) GetLanguageByID(ctx context.Context, id uuid.UUID) (database.Language, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	language, ok := s.languages[id]
+	if !ok {
+		return database.Language{}, sql.ErrNoRows
+	}
+	return language, nil
+}
+
+func (s *MemoryStore) UpdateLanguageName(ctx context.Context, arg database.UpdateLanguageNameParams) (database.Language, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	language, ok := s.getLanguage(arg.Name_2)
+	if !ok {
+		return database.Language{}, sql.ErrNoRows
+	}
+	if s.languageNameTaken(arg.Name, language.ID) {
+		return database.Language{}, duplicateKeyError("languages_name_key")
+	}
+
+	language.Name = arg.Name
+	language.UpdatedAt = now()
+	s.languages[language.ID] = language
+
+	return language, nil
+}
+
+func (s *MemoryStore) DeleteLanguage(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteLanguage(id)
+	return nil
+}
+
+// Deletes a language along with everything that cascades from it.
+func (s *MemoryStore) deleteLanguage(id uuid.UUID) {
+	delete(s.languages, id)
+	delete(s.phonologies, id)
+	for _, class := range s.inflectionClasses {
+		if class.LanguageID == id {
+			s.deleteInflectionClass(class.ID)
+		}
+	}
+	for _, rule := range s.lemmaRules {
+		if rule.LanguageID == id {
+			delete(s.lemmaRules, rule.ID)
+		}
+	}
+	for _, text := range s.texts {
+		if text.LanguageID == id {
+			delete(s.texts, text.ID)
+		}
+	}
+	for _, word := range s.words {
+		if word.LanguageID == id {
+			s.deleteWord(word.ID)
+		}
+	}
+}
+
+/*
+ * Words
+ */
+
+func (s *MemoryStore) wordTaken(languageID uuid.UUID, word string, except uuid.UUID) bool {
+	for _, w := range s.words {
+		if w.LanguageID == languageID && w.Word == word && w.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) insertWord(word string, formatted sql.NullString, languageID uuid.UUID) (database.Word, error) {
+	if _, ok := s.languages[languageID]; !ok {
+		return database.Word{}, fmt.Errorf("insert or update on table \"words\" violates foreign key constraint \"fk_language_id\"")
+	}
+	if s.wordTaken(languageID, word, uuid.Nil) {
+		return database.Word{}, duplicateKeyError("words_language_id_word_key")
+	}
+
+	t := now()
+	w := database.Word{
+		ID:            uuid.New(),
+		CreatedAt:     t,
+		UpdatedAt:     t,
+		Word:          word,
+		FontFormatted: formatted,
+		LanguageID:    languageID,
+	}
+	s.words[w.ID] = w
+
+	return w, nil
+}
+
+func (s *MemoryStore) CreateWord(ctx context.Context, arg database.CreateWordParams) (database.Word, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertWord(arg.Word, sql.NullString{}, arg.LanguageID)
+}
+
+func (s *MemoryStore) CreateFormattedWord(ctx context.Context, arg database.CreateFormattedWordParams) (database.Word, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertWord(arg.Word, arg.FontFormatted, arg.LanguageID)
+}
+
+// Returns all words matching the predicate, ordered by creation time.
+func (s *MemoryStore) filterWords(keep func(database.Word) bool) []database.Word {
+	words := []database.Word{}
+	for _, w := range sortedByCreation(s.words, func(w database.Word) time.Time { return w.CreatedAt }) {
+		if keep(w) {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+func (s *MemoryStore) GetWord(ctx context.Context, word string) ([]database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.filterWords(func(w database.Word) bool {
+		return strings.ToLower(w.Word) == word
+	}), nil
+}
+
+func (s *MemoryStore) GetWordByID(ctx context.Context, id uuid.UUID) (database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	word, ok := s.words[id]
+	if !ok {
+		return database.Word{}, sql.ErrNoRows
+	}
+	return word, nil
+}
+
+func (s *MemoryStore) GetWordFromLanguage(ctx context.Context, arg database.GetWordFromLanguageParams) (database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	words := s.filterWords(func(w database.Word) bool {
+		return w.LanguageID == arg.LanguageID && strings.ToLower(w.Word) == arg.Word
+	})
+	if len(words) == 0 {
+		return database.Word{}, sql.ErrNoRows
+	}
+	return words[0], nil
+}
+
+func (s *MemoryStore) GetWords(ctx context.Context) ([]database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.filterWords(func(database.Word) bool { return true }), nil
+}
+
+func (s *MemoryStore) GetWordsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	return s.filterWords(func(w database.Word) bool {
+		return wanted[w.ID]
+	}), nil
+}
+
+func (s *MemoryStore) GetWordsByLanguageID(ctx context.Context, languageID uuid.UUID) ([]database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.filterWords(func(w database.Word) bool {
+		return w.LanguageID == languageID
+	}), nil
+}
+
+func (s *MemoryStore) GetWordsFromLanguageByForms(ctx context.Context, arg database.GetWordsFromLanguageByFormsParams) ([]database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := map[string]bool{}
+	for _, form := range arg.Forms {
+		wanted[form] = true
+	}
+
+	return s.filterWords(func(w database.Word) bool {
+		return w.LanguageID == arg.LanguageID && wanted[strings.ToLower(w.Word)]
+	}), nil
+}
+
+func (s *MemoryStore) UpdateWord(ctx context.Context, arg database.UpdateWordParams) (database.Word, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	word, ok := s.words[arg.ID]
+	if !ok {
+		return database.Word{}, sql.ErrNoRows
+	}
+
+	if arg.SetWord {
+		if s.wordTaken(word.LanguageID, arg.Word, word.ID) {
+			return database.Word{}, duplicateKeyError("words_language_id_word_key")
+		}
+		word.Word = arg.Word
+	}
+	if arg.SetFormatted {
+		word.FontFormatted = sql.NullString{String: arg.Formatted, Valid: true}
+	}
+	word.UpdatedAt = now()
+	s.words[word.ID] = word
+
+	return word, nil
+}
+
+func (s *MemoryStore) deleteWord(id uuid.UUID) {
+	delete(s.words, id)
+	for _, d := range s.definitions {
+		if d.WordID == id {
+			s.deleteDefinition(d.ID)
+		}
+	}
+	for _, t := range s.translations {
+		if t.WordID == id || t.TranslationWordID == id {
+			delete(s.translations, t.ID)
+		}
+	}
+	for _, rel := range s.wordRelations {
+		if rel.WordID == id || rel.SourceWordID == id {
+			delete(s.wordRelations, rel.ID)
+		}
+	}
+	for _, e := range s.examples {
+		if e.WordID == id {
+			delete(s.examples, e.ID)
+		}
+	}
+	for key := range s.wordInflectionClasses {
+		if key.wordID == id {
+			delete(s.wordInflectionClasses, key)
+		}
+	}
+	for _, o := range s.inflectionOverrides {
+		if o.WordID == id {
+			delete(s.inflectionOverrides, o.ID)
+		}
+	}
+}
+
+func (s *MemoryStore) DeleteWord(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWord(id)
+	return nil
+}
+
+/*
+ * Definitions
+ */
+
+func (s *MemoryStore) definitionTaken(wordID uuid.UUID, content string, except uuid.UUID) bool {
+	for _, d := range s.definitions {
+		if d.WordID == wordID && d.Content == content && d.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) CreateDefinition(ctx context.Context, arg database.CreateDefinitionParams) (database.Definition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.words[arg.WordID]; !ok {
+		return database.Definition{}, fmt.Errorf("insert or update on table \"definitions\" violates foreign key constraint \"fk_word_id\"")
+	}
+	if s.definitionTaken(arg.WordID, arg.Content, uuid.Nil) {
+		return database.Definition{}, duplicateKeyError("definitions_word_id_content_key")
+	}
+
+	t := now()
+	definition := database.Definition{
+		ID:           uuid.New(),
+		CreatedAt:    t,
+		UpdatedAt:    t,
+		Content:      arg.Content,
+		PartOfSpeech: arg.PartOfSpeech,
+		WordID:       arg.WordID,
+	}
+	s.definitions[definition.ID] = definition
+
+	return definition, nil
+}
+
+func (s *MemoryStore) GetDefinitionsOfWord(ctx context.Context, wordID uuid.UUID) ([]database.Definition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	definitions := []database.Definition{}
+	for _, d := range s.definitions {
+		if d.WordID == wordID {
+			definitions = append(definitions, d)
+		}
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		if definitions[i].PartOfSpeech != definitions[j].PartOfSpeech {
+			return definitions[i].PartOfSpeech < definitions[j].PartOfSpeech
+		}
+		return definitions[i].Content < definitions[j].Content
+	})
+
+	return definitions, nil
+}
+
+func (s *MemoryStore) GetDefinitionsOfWords(ctx context.Context, wordIds []uuid.UUID) ([]database.Definition, error) {
+	definitions := []database.Definition{}
+	for _, id := range wordIds {
+		ofWord, _ := s.GetDefinitionsOfWord(ctx, id)
+		definitions = append(definitions, ofWord...)
+	}
+
+	return definitions, nil
+}
+
+func (s *MemoryStore) GetDefinitionByID(ctx context.Context, id uuid.UUID) (database.Definition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	definition, ok := s.definitions[id]
+	if !ok {
+		return database.Definition{}, sql.ErrNoRows
+	}
+	return definition, nil
+}
+
+// Applies an update to a single definition, enforcing the unique constraint on
+// (word_id, content).
+func (s *MemoryStore) updateDefinition(id uuid.UUID, update func(*database.Definition)) (database.Definition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	definition, ok := s.definitions[id]
+	if !ok {
+		return database.Definition{}, sql.ErrNoRows
+	}
+
+	update(&definition)
+	if s.definitionTaken(definition.WordID, definition.Content, definition.ID) {
+		return database.Definition{}, duplicateKeyError("definitions_word_id_content_key")
+	}
+	definition.UpdatedAt = now()
+	s.definitions[id] = definition
+
+	return definition, nil
+}
+
+func (s *MemoryStore) UpdateDefinition(ctx context.Context, arg database.UpdateDefinitionParams) (database.Definition, error) {
+	return s.updateDefinition(arg.ID, func(d *database.Definition) {
+		d.Content = arg.Content
+		d.PartOfSpeech = arg.PartOfSpeech
+	})
+}
+
+func (s *MemoryStore) UpdateDefinitionContent(ctx context.Context, arg database.UpdateDefinitionContentParams) (database.Definition, error) {
+	return s.updateDefinition(arg.ID, func(d *database.Definition) {
+		d.Content = arg.Content
+	})
+}
+
+func (s *MemoryStore) UpdateDefinitionPartOfSpeech(ctx context.Context, arg database.UpdateDefinitionPartOfSpeechParams) (database.Definition, error) {
+	return s.updateDefinition(arg.ID, func(d *database.Definition) {
+		d.PartOfSpeech = arg.PartOfSpeech
+	})
+}
+
+func (s *MemoryStore) deleteDefinition(id uuid.UUID) {
+	delete(s.definitions, id)
+	for _, t := range s.translations {
+		if t.DefinitionID.Valid && t.DefinitionID.UUID == id {
+			delete(s.translations, t.ID)
+		}
+	}
+	for _, e := range s.examples {
+		if e.DefinitionID.Valid && e.DefinitionID.UUID == id {
+			e.DefinitionID = uuid.NullUUID{}
+			s.examples[e.ID] = e
+		}
+	}
+}
+
+func (s *MemoryStore) DeleteDefinition(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteDefinition(id)
+	return nil
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"vastestsea/internal/database"
+)
+
+// Restores mirror INSERT ... ON CONFLICT (id) DO UPDATE ... WHERE the stored
+// row is older: a row is inserted if its ID is new, replaces the stored row
+// if it is newer, and is otherwise left alone. They report how many rows
+// changed.
+
+func (s *MemoryStore) RestoreLanguage(ctx context.Context, arg database.RestoreLanguageParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.languages[arg.ID]; ok && !stored.UpdatedAt.Before(arg.UpdatedAt) {
+		return 0, nil
+	}
+	if s.languageNameTaken(arg.Name, arg.ID) {
+		return 0, duplicateKeyError("languages_name_key")
+	}
+
+	s.languages[arg.ID] = database.Language{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Name:      arg.Name,
+	}
+	return 1, nil
+}
+
+func (s *MemoryStore) RestoreWord(ctx context.Context, arg database.RestoreWordParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.words[arg.ID]; ok && !stored.UpdatedAt.Before(arg.UpdatedAt) {
+		return 0, nil
+	}
+	if _, ok := s.languages[arg.LanguageID]; !ok {
+		return 0, fmt.Errorf("insert or update on table \"words\" violates foreign key constraint \"fk_language_id\"")
+	}
+	if s.wordTaken(arg.LanguageID, arg.Word, arg.ID) {
+		return 0, duplicateKeyError("words_language_id_word_key")
+	}
+
+	s.words[arg.ID] = database.Word{
+		ID:            arg.ID,
+		CreatedAt:     arg.CreatedAt,
+		UpdatedAt:     arg.UpdatedAt,
+		Word:          arg.Word,
+		FontFormatted: arg.FontFormatted,
+		LanguageID:    arg.LanguageID,
+	}
+	return 1, nil
+}
+
+func (s *MemoryStore) RestoreDefinition(ctx context.Context, arg database.RestoreDefinitionParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.definitions[arg.ID]; ok && !stored.UpdatedAt.Before(arg.UpdatedAt) {
+		return 0, nil
+	}
+	if _, ok := s.words[arg.WordID]; !ok {
+		return 0, fmt.Errorf("insert or update on table \"definitions\" violates foreign key constraint \"fk_word_id\"")
+	}
+	if s.definitionTaken(arg.WordID, arg.Content, arg.ID) {
+		return 0, duplicateKeyError("definitions_word_id_content_key")
+	}
+
+	s.definitions[arg.ID] = database.Definition{
+		ID:           arg.ID,
+		CreatedAt:    arg.CreatedAt,
+		UpdatedAt:    arg.UpdatedAt,
+		Content:      arg.Content,
+		PartOfSpeech: arg.PartOfSpeech,
+		WordID:       arg.WordID,
+	}
+	return 1, nil
+}
+
+func (s *MemoryStore) DeleteAllLanguages(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.languages {
+		s.deleteLanguage(id)
+	}
+	return nil
+}
+
+func (s *MemoryStore) CountUnarchivedRows(ctx context.Context) (database.CountUnarchivedRowsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return database.CountUnarchivedRowsRow{
+		WordRelations:     int64(len(s.wordRelations)),
+		Translations:      int64(len(s.translations)),
+		Phonologies:       int64(len(s.phonologies)),
+		InflectionClasses: int64(len(s.inflectionClasses)),
+		LemmaRules:        int64(len(s.lemmaRules)),
+		Examples:          int64(len(s.examples)),
+		Texts:             int64(len(s.texts)),
+	}, nil
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// The relation types allowed by the check constraint on word_relations.
+var wordRelationTypes = map[string]bool{
+	"inherited": true,
+	"derived":   true,
+	"borrowed":  true,
+	"compound":  true,
+}
+
+func (s *MemoryStore) CreateWordRelation(ctx context.Context, arg database.CreateWordRelationParams) (database.WordRelation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.words[arg.WordID]; !ok {
+		return database.WordRelation{}, errors.New("insert or update on table \"word_relations\" violates foreign key constraint \"fk_word_id\"")
+	}
+	if _, ok := s.words[arg.SourceWordID]; !ok {
+		return database.WordRelation{}, errors.New("insert or update on table \"word_relations\" violates foreign key constraint \"fk_source_word_id\"")
+	}
+	if arg.WordID == arg.SourceWordID || !wordRelationTypes[arg.RelationType] {
+		return database.WordRelation{}, fmt.Errorf("new row for relation \"word_relations\" violates check constraint")
+	}
+	for _, rel := range s.wordRelations {
+		if rel.WordID == arg.WordID && rel.SourceWordID == arg.SourceWordID && rel.RelationType == arg.RelationType {
+			return database.WordRelation{}, duplicateKeyError("word_relations_word_id_source_word_id_relation_type_key")
+		}
+	}
+
+	t := now()
+	relation := database.WordRelation{
+		ID:           uuid.New(),
+		CreatedAt:    t,
+		UpdatedAt:    t,
+		WordID:       arg.WordID,
+		SourceWordID: arg.SourceWordID,
+		RelationType: arg.RelationType,
+	}
+	s.wordRelations[relation.ID] = relation
+
+	return relation, nil
+}
+
+func (s *MemoryStore) GetWordRelationByID(ctx context.Context, id uuid.UUID) (database.WordRelation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	relation, ok := s.wordRelations[id]
+	if !ok {
+		return database.WordRelation{}, sql.ErrNoRows
+	}
+	return relation, nil
+}
+
+// Walks the relation graph from start, the way the recursive CTEs in
+// word_relations.sql do. follows reports whether rel leads on from the given
+// word, and which word it leads to.
+func (s *MemoryStore) walkRelations(
+	start uuid.UUID,
+	follows func(rel database.WordRelation, from uuid.UUID) (uuid.UUID, bool),
+) []database.WordRelation {
+	relations := sortedByCreation(s.wordRelations, func(r database.WordRelation) time.Time { return r.CreatedAt })
+
+	found := map[uuid.UUID]bool{}
+	visited := map[uuid.UUID]bool{start: true}
+	queue := []uuid.UUID{start}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+
+		for _, rel := range relations {
+			next, ok := follows(rel, from)
+			if !ok || found[rel.ID] {
+				continue
+			}
+			found[rel.ID] = true
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	result := []database.WordRelation{}
+	for _, rel := range relations {
+		if found[rel.ID] {
+			result = append(result, rel)
+		}
+	}
+	return result
+}
+
+func (s *MemoryStore) GetAncestorRelations(ctx context.Context, wordID uuid.UUID) ([]database.WordRelation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.walkRelations(wordID, func(rel database.WordRelation, from uuid.UUID) (uuid.UUID, bool) {
+		return rel.SourceWordID, rel.WordID == from
+	}), nil
+}
+
+func (s *MemoryStore) GetDescendantRelations(ctx context.Context, sourceWordID uuid.UUID) ([]database.WordRelation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.walkRelations(sourceWordID, func(rel database.WordRelation, from uuid.UUID) (uuid.UUID, bool) {
+		return rel.WordID, rel.SourceWordID == from
+	}), nil
+}
+
+func (s *MemoryStore) DeleteWordRelation(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.wordRelations, id)
+	return nil
+}
+
+// Transactions on the memory store already run one at a time.
+func (s *MemoryStore) LockWordRelations(ctx context.Context) error {
+	return nil
+}
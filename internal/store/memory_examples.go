@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func (s *MemoryStore) checkExampleKeys(wordID uuid.UUID, definitionID uuid.NullUUID) error {
+	if _, ok := s.words[wordID]; !ok {
+		return errors.New("insert or update on table \"examples\" violates foreign key constraint \"fk_word_id\"")
+	}
+	if definitionID.Valid {
+		if _, ok := s.definitions[definitionID.UUID]; !ok {
+			return errors.New("insert or update on table \"examples\" violates foreign key constraint \"fk_definition_id\"")
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) CreateExample(ctx context.Context, arg database.CreateExampleParams) (database.Example, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkExampleKeys(arg.WordID, arg.DefinitionID); err != nil {
+		return database.Example{}, err
+	}
+
+	created := now()
+	example := database.Example{
+		ID:           uuid.New(),
+		CreatedAt:    created,
+		UpdatedAt:    created,
+		WordID:       arg.WordID,
+		DefinitionID: arg.DefinitionID,
+		Source:       arg.Source,
+		Gloss:        arg.Gloss,
+		Translation:  arg.Translation,
+	}
+	s.examples[example.ID] = example
+
+	return example, nil
+}
+
+func (s *MemoryStore) GetExampleByID(ctx context.Context, id uuid.UUID) (database.Example, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	example, ok := s.examples[id]
+	if !ok {
+		return database.Example{}, sql.ErrNoRows
+	}
+	return example, nil
+}
+
+func (s *MemoryStore) filterExamples(keep func(database.Example) bool) []database.Example {
+	examples := []database.Example{}
+	for _, e := range sortedByCreation(s.examples, func(e database.Example) time.Time { return e.CreatedAt }) {
+		if keep(e) {
+			examples = append(examples, e)
+		}
+	}
+	return examples
+}
+
+func (s *MemoryStore) GetExamplesOfWord(ctx context.Context, wordID uuid.UUID) ([]database.Example, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.filterExamples(func(e database.Example) bool {
+		return e.WordID == wordID
+	}), nil
+}
+
+func (s *MemoryStore) GetExamplesOfLanguage(ctx context.Context, languageID uuid.UUID) ([]database.Example, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.filterExamples(func(e database.Example) bool {
+		return s.words[e.WordID].LanguageID == languageID
+	}), nil
+}
+
+func (s *MemoryStore) UpdateExample(ctx context.Context, arg database.UpdateExampleParams) (database.Example, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	example, ok := s.examples[arg.ID]
+	if !ok {
+		return database.Example{}, sql.ErrNoRows
+	}
+	if err := s.checkExampleKeys(example.WordID, arg.DefinitionID); err != nil {
+		return database.Example{}, err
+	}
+
+	example.DefinitionID = arg.DefinitionID
+	example.Source = arg.Source
+	example.Gloss = arg.Gloss
+	example.Translation = arg.Translation
+	example.UpdatedAt = now()
+	s.examples[example.ID] = example
+
+	return example, nil
+}
+
+func (s *MemoryStore) DeleteExample(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.examples, id)
+	return nil
+}
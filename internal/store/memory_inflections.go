@@ -0,0 +1,299 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// Primary key of word_inflection_classes.
+type wordClassKey struct {
+	wordID  uuid.UUID
+	classID uuid.UUID
+}
+
+func sortInflectionClasses(classes []database.InflectionClass) {
+	sort.Slice(classes, func(i, j int) bool {
+		if classes[i].PartOfSpeech != classes[j].PartOfSpeech {
+			return classes[i].PartOfSpeech < classes[j].PartOfSpeech
+		}
+		if classes[i].Name != classes[j].Name {
+			return classes[i].Name < classes[j].Name
+		}
+		return classes[i].ID.String() < classes[j].ID.String()
+	})
+}
+
+func (s *MemoryStore) inflectionClassTaken(languageID uuid.UUID, partOfSpeech, name string, except uuid.UUID) bool {
+	for _, c := range s.inflectionClasses {
+		if c.LanguageID == languageID && c.PartOfSpeech == partOfSpeech && c.Name == name && c.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) CreateInflectionClass(ctx context.Context, arg database.CreateInflectionClassParams) (database.InflectionClass, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.languages[arg.LanguageID]; !ok {
+		return database.InflectionClass{}, errors.New("insert or update on table \"inflection_classes\" violates foreign key constraint \"fk_language_id\"")
+	}
+	if s.inflectionClassTaken(arg.LanguageID, arg.PartOfSpeech, arg.Name, uuid.Nil) {
+		return database.InflectionClass{}, duplicateKeyError("inflection_classes_language_id_part_of_speech_name_key")
+	}
+
+	created := now()
+	class := database.InflectionClass{
+		ID:           uuid.New(),
+		CreatedAt:    created,
+		UpdatedAt:    created,
+		LanguageID:   arg.LanguageID,
+		PartOfSpeech: arg.PartOfSpeech,
+		Name:         arg.Name,
+	}
+	s.inflectionClasses[class.ID] = class
+
+	return class, nil
+}
+
+func (s *MemoryStore) GetInflectionClassByID(ctx context.Context, id uuid.UUID) (database.InflectionClass, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	class, ok := s.inflectionClasses[id]
+	if !ok {
+		return database.InflectionClass{}, sql.ErrNoRows
+	}
+	return class, nil
+}
+
+func (s *MemoryStore) GetInflectionClassesOfLanguage(ctx context.Context, languageID uuid.UUID) ([]database.InflectionClass, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	classes := []database.InflectionClass{}
+	for _, c := range s.inflectionClasses {
+		if c.LanguageID == languageID {
+			classes = append(classes, c)
+		}
+	}
+	sortInflectionClasses(classes)
+	return classes, nil
+}
+
+func (s *MemoryStore) UpdateInflectionClass(ctx context.Context, arg database.UpdateInflectionClassParams) (database.InflectionClass, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	class, ok := s.inflectionClasses[arg.ID]
+	if !ok {
+		return database.InflectionClass{}, sql.ErrNoRows
+	}
+	if s.inflectionClassTaken(class.LanguageID, arg.PartOfSpeech, arg.Name, class.ID) {
+		return database.InflectionClass{}, duplicateKeyError("inflection_classes_language_id_part_of_speech_name_key")
+	}
+
+	class.PartOfSpeech = arg.PartOfSpeech
+	class.Name = arg.Name
+	class.UpdatedAt = now()
+	s.inflectionClasses[class.ID] = class
+
+	return class, nil
+}
+
+func (s *MemoryStore) deleteInflectionClass(id uuid.UUID) {
+	delete(s.inflectionClasses, id)
+	for _, r := range s.inflectionRules {
+		if r.ClassID == id {
+			delete(s.inflectionRules, r.ID)
+		}
+	}
+	for key := range s.wordInflectionClasses {
+		if key.classID == id {
+			delete(s.wordInflectionClasses, key)
+		}
+	}
+	for _, o := range s.inflectionOverrides {
+		if o.ClassID == id {
+			delete(s.inflectionOverrides, o.ID)
+		}
+	}
+}
+
+func (s *MemoryStore) DeleteInflectionClass(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteInflectionClass(id)
+	return nil
+}
+
+func (s *MemoryStore) CreateInflectionRule(ctx context.Context, arg database.CreateInflectionRuleParams) (database.InflectionRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inflectionClasses[arg.ClassID]; !ok {
+		return database.InflectionRule{}, errors.New("insert or update on table \"inflection_rules\" violates foreign key constraint \"fk_class_id\"")
+	}
+	for _, r := range s.inflectionRules {
+		if r.ClassID == arg.ClassID && r.RowLabel == arg.RowLabel && r.ColumnLabel == arg.ColumnLabel {
+			return database.InflectionRule{}, duplicateKeyError("inflection_rules_class_id_row_label_column_label_key")
+		}
+	}
+
+	created := now()
+	rule := database.InflectionRule{
+		ID:          uuid.New(),
+		CreatedAt:   created,
+		UpdatedAt:   created,
+		ClassID:     arg.ClassID,
+		Position:    arg.Position,
+		RowLabel:    arg.RowLabel,
+		ColumnLabel: arg.ColumnLabel,
+		Strip:       arg.Strip,
+		Prefix:      arg.Prefix,
+		Suffix:      arg.Suffix,
+	}
+	s.inflectionRules[rule.ID] = rule
+
+	return rule, nil
+}
+
+func (s *MemoryStore) GetInflectionRulesOfClass(ctx context.Context, classID uuid.UUID) ([]database.InflectionRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules := []database.InflectionRule{}
+	for _, r := range s.inflectionRules {
+		if r.ClassID == classID {
+			rules = append(rules, r)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Position != rules[j].Position {
+			return rules[i].Position < rules[j].Position
+		}
+		return rules[i].ID.String() < rules[j].ID.String()
+	})
+	return rules, nil
+}
+
+func (s *MemoryStore) DeleteInflectionRulesOfClass(ctx context.Context, classID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.inflectionRules {
+		if r.ClassID == classID {
+			delete(s.inflectionRules, r.ID)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) AssignInflectionClass(ctx context.Context, arg database.AssignInflectionClassParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.words[arg.WordID]; !ok {
+		return errors.New("insert or update on table \"word_inflection_classes\" violates foreign key constraint \"fk_word_id\"")
+	}
+	if _, ok := s.inflectionClasses[arg.ClassID]; !ok {
+		return errors.New("insert or update on table \"word_inflection_classes\" violates foreign key constraint \"fk_class_id\"")
+	}
+
+	key := wordClassKey{wordID: arg.WordID, classID: arg.ClassID}
+	if _, ok := s.wordInflectionClasses[key]; !ok {
+		s.wordInflectionClasses[key] = database.WordInflectionClass{
+			WordID:    arg.WordID,
+			ClassID:   arg.ClassID,
+			CreatedAt: now(),
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetInflectionClassesOfWord(ctx context.Context, wordID uuid.UUID) ([]database.InflectionClass, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	classes := []database.InflectionClass{}
+	for key := range s.wordInflectionClasses {
+		if key.wordID == wordID {
+			classes = append(classes, s.inflectionClasses[key.classID])
+		}
+	}
+	sortInflectionClasses(classes)
+	return classes, nil
+}
+
+func (s *MemoryStore) UnassignInflectionClass(ctx context.Context, arg database.UnassignInflectionClassParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.wordInflectionClasses, wordClassKey{wordID: arg.WordID, classID: arg.ClassID})
+	return nil
+}
+
+func (s *MemoryStore) CreateInflectionOverride(ctx context.Context, arg database.CreateInflectionOverrideParams) (database.InflectionOverride, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.words[arg.WordID]; !ok {
+		return database.InflectionOverride{}, errors.New("insert or update on table \"inflection_overrides\" violates foreign key constraint \"fk_word_id\"")
+	}
+	if _, ok := s.inflectionClasses[arg.ClassID]; !ok {
+		return database.InflectionOverride{}, errors.New("insert or update on table \"inflection_overrides\" violates foreign key constraint \"fk_class_id\"")
+	}
+	for _, o := range s.inflectionOverrides {
+		if o.WordID == arg.WordID && o.ClassID == arg.ClassID && o.RowLabel == arg.RowLabel && o.ColumnLabel == arg.ColumnLabel {
+			return database.InflectionOverride{}, duplicateKeyError("inflection_overrides_word_id_class_id_row_label_column_label_key")
+		}
+	}
+
+	created := now()
+	override := database.InflectionOverride{
+		ID:          uuid.New(),
+		CreatedAt:   created,
+		UpdatedAt:   created,
+		WordID:      arg.WordID,
+		ClassID:     arg.ClassID,
+		RowLabel:    arg.RowLabel,
+		ColumnLabel: arg.ColumnLabel,
+		Form:        arg.Form,
+	}
+	s.inflectionOverrides[override.ID] = override
+
+	return override, nil
+}
+
+func (s *MemoryStore) GetInflectionOverridesOfWord(ctx context.Context, wordID uuid.UUID) ([]database.InflectionOverride, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	overrides := []database.InflectionOverride{}
+	for _, o := range sortedByCreation(s.inflectionOverrides, func(o database.InflectionOverride) time.Time { return o.CreatedAt }) {
+		if o.WordID == wordID {
+			overrides = append(overrides, o)
+		}
+	}
+	return overrides, nil
+}
+
+func (s *MemoryStore) DeleteInflectionOverrides(ctx context.Context, arg database.DeleteInflectionOverridesParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, o := range s.inflectionOverrides {
+		if o.WordID == arg.WordID && o.ClassID == arg.ClassID {
+			delete(s.inflectionOverrides, o.ID)
+		}
+	}
+	return nil
+}
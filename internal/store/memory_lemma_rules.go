@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func (s *MemoryStore) CreateLemmaRule(ctx context.Context, arg database.CreateLemmaRuleParams) (database.LemmaRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.languages[arg.LanguageID]; !ok {
+		return database.LemmaRule{}, errors.New("insert or update on table \"lemma_rules\" violates foreign key constraint \"fk_language_id\"")
+	}
+	switch arg.Kind {
+	case "prefix", "suffix", "circumfix":
+	default:
+		return database.LemmaRule{}, errors.New("new row for relation \"lemma_rules\" violates check constraint")
+	}
+	for _, r := range s.lemmaRules {
+		if r.LanguageID == arg.LanguageID && r.Prefix == arg.Prefix && r.Suffix == arg.Suffix &&
+			r.Restore == arg.Restore && r.Features == arg.Features {
+			return database.LemmaRule{}, duplicateKeyError("lemma_rules_language_id_prefix_suffix_restore_features_key")
+		}
+	}
+
+	created := now()
+	rule := database.LemmaRule{
+		ID:         uuid.New(),
+		CreatedAt:  created,
+		UpdatedAt:  created,
+		LanguageID: arg.LanguageID,
+		Kind:       arg.Kind,
+		Prefix:     arg.Prefix,
+		Suffix:     arg.Suffix,
+		Restore:    arg.Restore,
+		Features:   arg.Features,
+	}
+	s.lemmaRules[rule.ID] = rule
+
+	return rule, nil
+}
+
+func (s *MemoryStore) GetLemmaRuleByID(ctx context.Context, id uuid.UUID) (database.LemmaRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rule, ok := s.lemmaRules[id]
+	if !ok {
+		return database.LemmaRule{}, sql.ErrNoRows
+	}
+	return rule, nil
+}
+
+func (s *MemoryStore) GetLemmaRulesOfLanguage(ctx context.Context, languageID uuid.UUID) ([]database.LemmaRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules := []database.LemmaRule{}
+	for _, r := range sortedByCreation(s.lemmaRules, func(r database.LemmaRule) time.Time { return r.CreatedAt }) {
+		if r.LanguageID == languageID {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+func (s *MemoryStore) DeleteLemmaRule(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.lemmaRules, id)
+	return nil
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// Keyset pagination over an in-memory table, mirroring the
+// `WHERE (key, id) > (after_key, after_id) ORDER BY key, id LIMIT n` shape of
+// the List* queries.
+func keysetPage[T any, K any](
+	items []T,
+	key func(T) K,
+	id func(T) uuid.UUID,
+	compare func(a, b K) int,
+	afterKey K,
+	afterID uuid.UUID,
+	limit int32,
+) []T {
+	compareRows := func(aKey K, aID uuid.UUID, bKey K, bID uuid.UUID) int {
+		if c := compare(aKey, bKey); c != 0 {
+			return c
+		}
+		return bytes.Compare(aID[:], bID[:])
+	}
+
+	page := []T{}
+	for _, item := range items {
+		if compareRows(key(item), id(item), afterKey, afterID) > 0 {
+			page = append(page, item)
+		}
+	}
+	sort.Slice(page, func(i, j int) bool {
+		return compareRows(key(page[i]), id(page[i]), key(page[j]), id(page[j])) < 0
+	})
+
+	if limit >= 0 && int(limit) < len(page) {
+		page = page[:limit]
+	}
+	return page
+}
+
+// Picks the timestamp the ByTime queries order on.
+func timeKey(byUpdated bool, createdAt, updatedAt time.Time) time.Time {
+	if byUpdated {
+		return updatedAt
+	}
+	return createdAt
+}
+
+func (s *MemoryStore) ListLanguagesAlphabetical(ctx context.Context, arg database.ListLanguagesAlphabeticalParams) ([]database.Language, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return keysetPage(
+		sortedByCreation(s.languages, func(l database.Language) time.Time { return l.CreatedAt }),
+		func(l database.Language) string { return strings.ToLower(l.Name) },
+		func(l database.Language) uuid.UUID { return l.ID },
+		strings.Compare,
+		arg.AfterName,
+		arg.AfterID,
+		arg.MaxResults,
+	), nil
+}
+
+func (s *MemoryStore) ListLanguagesByTime(ctx context.Context, arg database.ListLanguagesByTimeParams) ([]database.Language, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return keysetPage(
+		sortedByCreation(s.languages, func(l database.Language) time.Time { return l.CreatedAt }),
+		func(l database.Language) time.Time { return timeKey(arg.ByUpdated, l.CreatedAt, l.UpdatedAt) },
+		func(l database.Language) uuid.UUID { return l.ID },
+		time.Time.Compare,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.MaxResults,
+	), nil
+}
+
+func (s *MemoryStore) ListWordsAlphabetical(ctx context.Context, arg database.ListWordsAlphabeticalParams) ([]database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	words := s.filterWords(func(w database.Word) bool {
+		return !arg.LanguageID.Valid || w.LanguageID == arg.LanguageID.UUID
+	})
+	return keysetPage(
+		words,
+		func(w database.Word) string { return strings.ToLower(w.Word) },
+		func(w database.Word) uuid.UUID { return w.ID },
+		strings.Compare,
+		arg.AfterWord,
+		arg.AfterID,
+		arg.MaxResults,
+	), nil
+}
+
+func (s *MemoryStore) ListWordsByTime(ctx context.Context, arg database.ListWordsByTimeParams) ([]database.Word, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	words := s.filterWords(func(w database.Word) bool {
+		return !arg.LanguageID.Valid || w.LanguageID == arg.LanguageID.UUID
+	})
+	return keysetPage(
+		words,
+		func(w database.Word) time.Time { return timeKey(arg.ByUpdated, w.CreatedAt, w.UpdatedAt) },
+		func(w database.Word) uuid.UUID { return w.ID },
+		time.Time.Compare,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.MaxResults,
+	), nil
+}
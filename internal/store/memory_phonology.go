@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func (s *MemoryStore) GetPhonology(ctx context.Context, languageID uuid.UUID) (database.Phonology, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	phonology, ok := s.phonologies[languageID]
+	if !ok {
+		return database.Phonology{}, sql.ErrNoRows
+	}
+	return phonology, nil
+}
+
+func (s *MemoryStore) UpsertPhonology(ctx context.Context, arg database.UpsertPhonologyParams) (database.Phonology, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.languages[arg.LanguageID]; !ok {
+		return database.Phonology{}, errors.New("insert or update on table \"phonologies\" violates foreign key constraint \"fk_language_id\"")
+	}
+
+	t := now()
+	phonology, ok := s.phonologies[arg.LanguageID]
+	if !ok {
+		phonology = database.Phonology{
+			LanguageID: arg.LanguageID,
+			CreatedAt:  t,
+		}
+	}
+	phonology.UpdatedAt = t
+	phonology.Phonemes = slices.Clone(arg.Phonemes)
+	phonology.Categories = slices.Clone(arg.Categories)
+	phonology.SyllableTemplates = slices.Clone(arg.SyllableTemplates)
+	phonology.ForbiddenClusters = slices.Clone(arg.ForbiddenClusters)
+	s.phonologies[arg.LanguageID] = phonology
+
+	return phonology, nil
+}
+
+func (s *MemoryStore) DeletePhonology(ctx context.Context, languageID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.phonologies, languageID)
+	return nil
+}
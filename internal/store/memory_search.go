@@ -0,0 +1,333 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// A small stand-in for Postgres' english text search configuration, which
+// is enough for the in-memory store to answer the same kinds of queries.
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"to": true, "was": true, "with": true,
+}
+
+// Crude suffix stripping, so that "rivers" matches "river".
+func stem(token string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(token, suffix) && len(token)-len(suffix) >= 3 {
+			return strings.TrimSuffix(token, suffix)
+		}
+	}
+	return token
+}
+
+type textSpan struct {
+	start, end int
+}
+
+// Finds the byte offsets of every word in text.
+func wordSpans(text string) []textSpan {
+	spans := []textSpan{}
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			spans = append(spans, textSpan{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, textSpan{start, len(text)})
+	}
+	return spans
+}
+
+// Splits a websearch-style query into the stems that must, and must not,
+// appear. Quotes are ignored, and "or" is treated as a stop word.
+func parseSearchQuery(query string) (include, exclude []string) {
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		negated := strings.HasPrefix(field, "-")
+		for _, span := range wordSpans(field) {
+			token := field[span.start:span.end]
+			if searchStopWords[token] {
+				continue
+			}
+			if negated {
+				exclude = append(exclude, stem(token))
+			} else {
+				include = append(include, stem(token))
+			}
+		}
+	}
+	return include, exclude
+}
+
+// Scores content against a parsed query, returning the rank, the content with
+// matching words between HighlightStart and HighlightStop, and whether it
+// matched at all.
+func matchDefinition(content string, include, exclude []string) (float32, string, bool) {
+	if len(include) == 0 {
+		return 0, "", false
+	}
+
+	spans := wordSpans(content)
+	stems := map[string]int{}
+	for _, span := range spans {
+		stems[stem(strings.ToLower(content[span.start:span.end]))]++
+	}
+
+	hits := 0
+	for _, term := range include {
+		if stems[term] == 0 {
+			return 0, "", false
+		}
+		hits += stems[term]
+	}
+	for _, term := range exclude {
+		if stems[term] > 0 {
+			return 0, "", false
+		}
+	}
+
+	wanted := map[string]bool{}
+	for _, term := range include {
+		wanted[term] = true
+	}
+
+	var highlighted strings.Builder
+	last := 0
+	for _, span := range spans {
+		if !wanted[stem(strings.ToLower(content[span.start:span.end]))] {
+			continue
+		}
+		highlighted.WriteString(content[last:span.start])
+		highlighted.WriteString(HighlightStart)
+		highlighted.WriteString(content[span.start:span.end])
+		highlighted.WriteString(HighlightStop)
+		last = span.end
+	}
+	highlighted.WriteString(content[last:])
+
+	return float32(hits) / float32(len(spans)), highlighted.String(), true
+}
+
+func (s *MemoryStore) SearchDefinitions(ctx context.Context, arg database.SearchDefinitionsParams) ([]database.SearchDefinitionsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	include, exclude := parseSearchQuery(arg.Query)
+
+	rows := []database.SearchDefinitionsRow{}
+	for _, d := range s.definitions {
+		if arg.PartOfSpeech.Valid && d.PartOfSpeech != arg.PartOfSpeech.String {
+			continue
+		}
+		word, ok := s.words[d.WordID]
+		if !ok || (arg.LanguageID.Valid && word.LanguageID != arg.LanguageID.UUID) {
+			continue
+		}
+
+		rank, highlighted, ok := matchDefinition(d.Content, include, exclude)
+		if !ok {
+			continue
+		}
+
+		rows = append(rows, database.SearchDefinitionsRow{
+			Word:        word,
+			Definition:  d,
+			Rank:        rank,
+			Highlighted: highlighted,
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Rank != rows[j].Rank {
+			return rows[i].Rank > rows[j].Rank
+		}
+		if a, b := strings.ToLower(rows[i].Word.Word), strings.ToLower(rows[j].Word.Word); a != b {
+			return a < b
+		}
+		return rows[i].Definition.ID.String() < rows[j].Definition.ID.String()
+	})
+	if int(arg.MaxResults) < len(rows) {
+		rows = rows[:arg.MaxResults]
+	}
+
+	return rows, nil
+}
+
+// The set of trigrams pg_trgm extracts from text: each alphanumeric word is
+// lowercased and padded with two leading spaces and one trailing space.
+func trigrams(text string) map[string]bool {
+	set := map[string]bool{}
+	lower := strings.ToLower(text)
+	for _, span := range wordSpans(lower) {
+		padded := []rune("  " + lower[span.start:span.end] + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// Mirrors pg_trgm's similarity(): shared trigrams over total distinct
+// trigrams.
+func similarity(a, b string) float32 {
+	aSet, bSet := trigrams(a), trigrams(b)
+	if len(aSet) == 0 || len(bSet) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for t := range aSet {
+		if bSet[t] {
+			shared++
+		}
+	}
+
+	return float32(shared) / float32(len(aSet)+len(bSet)-shared)
+}
+
+func wordSimilarity(w database.Word, query string) float32 {
+	return max(
+		similarity(strings.ToLower(w.Word), query),
+		similarity(strings.ToLower(w.FontFormatted.String), query),
+	)
+}
+
+// Mirrors SQL LIKE: % matches any run of characters, _ any single character,
+// and a backslash escapes the character after it.
+func matchLike(text, pattern string) bool {
+	t, p := []rune(text), []rune(pattern)
+
+	var match func(ti, pi int) bool
+	match = func(ti, pi int) bool {
+		for pi < len(p) {
+			switch p[pi] {
+			case '%':
+				for pi < len(p) && p[pi] == '%' {
+					pi++
+				}
+				if pi == len(p) {
+					return true
+				}
+				for k := ti; k <= len(t); k++ {
+					if match(k, pi) {
+						return true
+					}
+				}
+				return false
+			case '_':
+				if ti == len(t) {
+					return false
+				}
+			case '\\':
+				pi++
+				if pi == len(p) || ti == len(t) || t[ti] != p[pi] {
+					return false
+				}
+			default:
+				if ti == len(t) || t[ti] != p[pi] {
+					return false
+				}
+			}
+			ti++
+			pi++
+		}
+		return ti == len(t)
+	}
+
+	return match(0, 0)
+}
+
+type wordMatch struct {
+	word       database.Word
+	similarity float32
+}
+
+// Scores and filters the words of the store, ordered the same way as the
+// SearchWords* queries.
+func (s *MemoryStore) matchWords(
+	languageID uuid.NullUUID,
+	query string,
+	keep func(database.Word, float32) bool,
+	limit int32,
+) []wordMatch {
+	matches := []wordMatch{}
+	for _, w := range s.words {
+		if languageID.Valid && w.LanguageID != languageID.UUID {
+			continue
+		}
+		sim := wordSimilarity(w, query)
+		if keep(w, sim) {
+			matches = append(matches, wordMatch{w, sim})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].similarity != matches[j].similarity {
+			return matches[i].similarity > matches[j].similarity
+		}
+		if a, b := strings.ToLower(matches[i].word.Word), strings.ToLower(matches[j].word.Word); a != b {
+			return a < b
+		}
+		return matches[i].word.ID.String() < matches[j].word.ID.String()
+	})
+	if int(limit) < len(matches) {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+func (s *MemoryStore) SearchWordsFuzzy(ctx context.Context, arg database.SearchWordsFuzzyParams) ([]database.SearchWordsFuzzyRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := []database.SearchWordsFuzzyRow{}
+	matches := s.matchWords(arg.LanguageID, arg.Query, func(_ database.Word, sim float32) bool {
+		return sim >= s.similarityThreshold
+	}, arg.MaxResults)
+	for _, m := range matches {
+		rows = append(rows, database.SearchWordsFuzzyRow{Word: m.word, Similarity: m.similarity})
+	}
+
+	return rows, nil
+}
+
+func (s *MemoryStore) SearchWordsPattern(ctx context.Context, arg database.SearchWordsPatternParams) ([]database.SearchWordsPatternRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := []database.SearchWordsPatternRow{}
+	matches := s.matchWords(arg.LanguageID, arg.Query, func(w database.Word, _ float32) bool {
+		return matchLike(strings.ToLower(w.Word), arg.Pattern) ||
+			(w.FontFormatted.Valid && matchLike(strings.ToLower(w.FontFormatted.String), arg.Pattern))
+	}, arg.MaxResults)
+	for _, m := range matches {
+		rows = append(rows, database.SearchWordsPatternRow{Word: m.word, Similarity: m.similarity})
+	}
+
+	return rows, nil
+}
+
+// The default of pg_trgm.similarity_threshold.
+const defaultSimilarityThreshold = 0.3
+
+func (s *MemoryStore) SetSimilarityThreshold(ctx context.Context, threshold float32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.similarityThreshold = threshold
+	return nil
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func (s *MemoryStore) textTitleTaken(languageID uuid.UUID, title string, except uuid.UUID) bool {
+	for _, t := range s.texts {
+		if t.LanguageID == languageID && t.Title == title && t.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) CreateText(ctx context.Context, arg database.CreateTextParams) (database.Text, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.languages[arg.LanguageID]; !ok {
+		return database.Text{}, errors.New("insert or update on table \"texts\" violates foreign key constraint \"fk_language_id\"")
+	}
+	if s.textTitleTaken(arg.LanguageID, arg.Title, uuid.Nil) {
+		return database.Text{}, duplicateKeyError("texts_language_id_title_key")
+	}
+
+	created := now()
+	text := database.Text{
+		ID:         uuid.New(),
+		CreatedAt:  created,
+		UpdatedAt:  created,
+		LanguageID: arg.LanguageID,
+		Title:      arg.Title,
+		Content:    arg.Content,
+	}
+	s.texts[text.ID] = text
+
+	return text, nil
+}
+
+func (s *MemoryStore) GetTextByID(ctx context.Context, id uuid.UUID) (database.Text, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	text, ok := s.texts[id]
+	if !ok {
+		return database.Text{}, sql.ErrNoRows
+	}
+	return text, nil
+}
+
+func (s *MemoryStore) GetTextsOfLanguage(ctx context.Context, languageID uuid.UUID) ([]database.Text, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	texts := []database.Text{}
+	for _, t := range s.texts {
+		if t.LanguageID == languageID {
+			texts = append(texts, t)
+		}
+	}
+	sort.Slice(texts, func(i, j int) bool {
+		if texts[i].Title != texts[j].Title {
+			return texts[i].Title < texts[j].Title
+		}
+		return texts[i].ID.String() < texts[j].ID.String()
+	})
+	return texts, nil
+}
+
+func (s *MemoryStore) UpdateText(ctx context.Context, arg database.UpdateTextParams) (database.Text, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	text, ok := s.texts[arg.ID]
+	if !ok {
+		return database.Text{}, sql.ErrNoRows
+	}
+	if s.textTitleTaken(text.LanguageID, arg.Title, text.ID) {
+		return database.Text{}, duplicateKeyError("texts_language_id_title_key")
+	}
+
+	text.Title = arg.Title
+	text.Content = arg.Content
+	text.UpdatedAt = now()
+	s.texts[text.ID] = text
+
+	return text, nil
+}
+
+func (s *MemoryStore) DeleteText(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.texts, id)
+	return nil
+}
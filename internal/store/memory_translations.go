@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func (s *MemoryStore) CreateTranslation(ctx context.Context, arg database.CreateTranslationParams) (database.Translation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.words[arg.WordID]; !ok {
+		return database.Translation{}, errors.New("insert or update on table \"translations\" violates foreign key constraint \"fk_word_id\"")
+	}
+	if _, ok := s.words[arg.TranslationWordID]; !ok {
+		return database.Translation{}, errors.New("insert or update on table \"translations\" violates foreign key constraint \"fk_translation_word_id\"")
+	}
+	if arg.DefinitionID.Valid {
+		if _, ok := s.definitions[arg.DefinitionID.UUID]; !ok {
+			return database.Translation{}, errors.New("insert or update on table \"translations\" violates foreign key constraint \"fk_definition_id\"")
+		}
+	}
+	if arg.WordID == arg.TranslationWordID {
+		return database.Translation{}, errors.New("new row for relation \"translations\" violates check constraint")
+	}
+	for _, t := range s.translations {
+		forward := t.WordID == arg.WordID && t.TranslationWordID == arg.TranslationWordID
+		reverse := t.WordID == arg.TranslationWordID && t.TranslationWordID == arg.WordID
+		if (forward || reverse) && t.DefinitionID == arg.DefinitionID {
+			return database.Translation{}, duplicateKeyError("translations_unique_idx")
+		}
+	}
+
+	created := now()
+	translation := database.Translation{
+		ID:                uuid.New(),
+		CreatedAt:         created,
+		UpdatedAt:         created,
+		WordID:            arg.WordID,
+		TranslationWordID: arg.TranslationWordID,
+		DefinitionID:      arg.DefinitionID,
+	}
+	s.translations[translation.ID] = translation
+
+	return translation, nil
+}
+
+func (s *MemoryStore) GetTranslationByID(ctx context.Context, id uuid.UUID) (database.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	translation, ok := s.translations[id]
+	if !ok {
+		return database.Translation{}, sql.ErrNoRows
+	}
+	return translation, nil
+}
+
+func (s *MemoryStore) GetTranslationsOfWord(ctx context.Context, wordID uuid.UUID) ([]database.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	translations := []database.Translation{}
+	for _, t := range sortedByCreation(s.translations, func(t database.Translation) time.Time { return t.CreatedAt }) {
+		if t.WordID == wordID || t.TranslationWordID == wordID {
+			translations = append(translations, t)
+		}
+	}
+	return translations, nil
+}
+
+func (s *MemoryStore) DeleteTranslation(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.translations, id)
+	return nil
+}
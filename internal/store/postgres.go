@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"vastestsea/internal/database"
+)
+
+// Postgres-backed Store. All queries are provided by the embedded sqlc
+// generated *database.Queries.
+//
+// db is nil for stores bound to a transaction.
+type PostgresStore struct {
+	*database.Queries
+	db *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{
+		Queries: database.New(db),
+		db:      db,
+	}
+}
+
+// Runs fn inside a database transaction, committing if fn succeeds and rolling
+// back otherwise. Nested calls reuse the outer transaction.
+func (s *PostgresStore) RunInTx(ctx context.Context, fn func(Store) error) error {
+	if s.db == nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&PostgresStore{Queries: s.Queries.WithTx(tx)}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Runs fn inside a read-only REPEATABLE READ transaction, so that every query
+// sees the same snapshot of the database. Nested calls reuse the outer
+// transaction.
+func (s *PostgresStore) RunInSnapshot(ctx context.Context, fn func(Store) error) error {
+	if s.db == nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&PostgresStore{Queries: s.Queries.WithTx(tx)}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// Returned by backends that enforce the unique constraints from sql/schema
+// themselves, rather than relying on Postgres to report them.
+var ErrDuplicateKey = errors.New("duplicate key value violates unique constraint")
+
+// SearchDefinitions marks the matching words of a definition's Highlighted
+// text with these control characters, rather than with markup, so that the
+// text can be escaped before the marks are turned into HTML.
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// Store is the storage layer used by the API handlers. Its method set mirrors
+// the sqlc-generated queries in internal/database, so that the Postgres
+// backend can simply embed *database.Queries, and other backends only need to
+// honor the same semantics.
+//
+// Lookups that find nothing return sql.ErrNoRows, as database/sql does.
+type Store interface {
+	// Runs fn with a Store whose writes are applied atomically: either every
+	// write made through it persists, or, if fn returns an error, none do.
+	RunInTx(ctx context.Context, fn func(Store) error) error
+	// Runs fn with a read-only Store that sees every table as it was when fn
+	// began, however long fn takes and whatever is written meanwhile.
+	RunInSnapshot(ctx context.Context, fn func(Store) error) error
+
+	// Languages
+	CreateLanguage(ctx context.Context, name string) (database.Language, error)
+	GetLanguages(ctx context.Context) ([]database.Language, error)
+	GetLanguage(ctx context.Context, name string) (database.Language, error)
+	GetLanguageByID(ctx context.Context, id uuid.UUID) (database.Language, error)
+	UpdateLanguageName(ctx context.Context, arg database.UpdateLanguageNameParams) (database.Language, error)
+	DeleteLanguage(ctx context.Context, id uuid.UUID) error
+	ListLanguagesAlphabetical(ctx context.Context, arg database.ListLanguagesAlphabeticalParams) ([]database.Language, error)
+	ListLanguagesByTime(ctx context.Context, arg database.ListLanguagesByTimeParams) ([]database.Language, error)
+
+	// Phonology
+	GetPhonology(ctx context.Context, languageID uuid.UUID) (database.Phonology, error)
+	UpsertPhonology(ctx context.Context, arg database.UpsertPhonologyParams) (database.Phonology, error)
+	DeletePhonology(ctx context.Context, languageID uuid.UUID) error
+
+	// Words
+	CreateWord(ctx context.Context, arg database.CreateWordParams) (database.Word, error)
+	CreateFormattedWord(ctx context.Context, arg database.CreateFormattedWordParams) (database.Word, error)
+	GetWord(ctx context.Context, word string) ([]database.Word, error)
+	GetWordByID(ctx context.Context, id uuid.UUID) (database.Word, error)
+	GetWordFromLanguage(ctx context.Context, arg database.GetWordFromLanguageParams) (database.Word, error)
+	GetWords(ctx context.Context) ([]database.Word, error)
+	GetWordsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.Word, error)
+	GetWordsByLanguageID(ctx context.Context, languageID uuid.UUID) ([]database.Word, error)
+	UpdateWord(ctx context.Context, arg database.UpdateWordParams) (database.Word, error)
+	DeleteWord(ctx context.Context, id uuid.UUID) error
+	ListWordsAlphabetical(ctx context.Context, arg database.ListWordsAlphabeticalParams) ([]database.Word, error)
+	ListWordsByTime(ctx context.Context, arg database.ListWordsByTimeParams) ([]database.Word, error)
+	GetWordsFromLanguageByForms(ctx context.Context, arg database.GetWordsFromLanguageByFormsParams) ([]database.Word, error)
+
+	// Definitions
+	CreateDefinition(ctx context.Context, arg database.CreateDefinitionParams) (database.Definition, error)
+	GetDefinitionsOfWord(ctx context.Context, wordID uuid.UUID) ([]database.Definition, error)
+	GetDefinitionsOfWords(ctx context.Context, wordIds []uuid.UUID) ([]database.Definition, error)
+	GetDefinitionByID(ctx context.Context, id uuid.UUID) (database.Definition, error)
+	UpdateDefinition(ctx context.Context, arg database.UpdateDefinitionParams) (database.Definition, error)
+	UpdateDefinitionContent(ctx context.Context, arg database.UpdateDefinitionContentParams) (database.Definition, error)
+	UpdateDefinitionPartOfSpeech(ctx context.Context, arg database.UpdateDefinitionPartOfSpeechParams) (database.Definition, error)
+	DeleteDefinition(ctx context.Context, id uuid.UUID) error
+
+	// Examples
+	CreateExample(ctx context.Context, arg database.CreateExampleParams) (database.Example, error)
+	GetExampleByID(ctx context.Context, id uuid.UUID) (database.Example, error)
+	GetExamplesOfWord(ctx context.Context, wordID uuid.UUID) ([]database.Example, error)
+	GetExamplesOfLanguage(ctx context.Context, languageID uuid.UUID) ([]database.Example, error)
+	UpdateExample(ctx context.Context, arg database.UpdateExampleParams) (database.Example, error)
+	DeleteExample(ctx context.Context, id uuid.UUID) error
+
+	// Etymology
+	CreateWordRelation(ctx context.Context, arg database.CreateWordRelationParams) (database.WordRelation, error)
+	GetWordRelationByID(ctx context.Context, id uuid.UUID) (database.WordRelation, error)
+	GetAncestorRelations(ctx context.Context, wordID uuid.UUID) ([]database.WordRelation, error)
+	GetDescendantRelations(ctx context.Context, sourceWordID uuid.UUID) ([]database.WordRelation, error)
+	DeleteWordRelation(ctx context.Context, id uuid.UUID) error
+	// Blocks other transactions that take the same lock until the calling
+	// transaction ends, so that checks on the graph stay true until commit.
+	LockWordRelations(ctx context.Context) error
+
+	// Translations
+	CreateTranslation(ctx context.Context, arg database.CreateTranslationParams) (database.Translation, error)
+	GetTranslationByID(ctx context.Context, id uuid.UUID) (database.Translation, error)
+	GetTranslationsOfWord(ctx context.Context, wordID uuid.UUID) ([]database.Translation, error)
+	DeleteTranslation(ctx context.Context, id uuid.UUID) error
+
+	// Inflection
+	CreateInflectionClass(ctx context.Context, arg database.CreateInflectionClassParams) (database.InflectionClass, error)
+	GetInflectionClassByID(ctx context.Context, id uuid.UUID) (database.InflectionClass, error)
+	GetInflectionClassesOfLanguage(ctx context.Context, languageID uuid.UUID) ([]database.InflectionClass, error)
+	UpdateInflectionClass(ctx context.Context, arg database.UpdateInflectionClassParams) (database.InflectionClass, error)
+	DeleteInflectionClass(ctx context.Context, id uuid.UUID) error
+	CreateInflectionRule(ctx context.Context, arg database.CreateInflectionRuleParams) (database.InflectionRule, error)
+	GetInflectionRulesOfClass(ctx context.Context, classID uuid.UUID) ([]database.InflectionRule, error)
+	DeleteInflectionRulesOfClass(ctx context.Context, classID uuid.UUID) error
+	AssignInflectionClass(ctx context.Context, arg database.AssignInflectionClassParams) error
+	GetInflectionClassesOfWord(ctx context.Context, wordID uuid.UUID) ([]database.InflectionClass, error)
+	UnassignInflectionClass(ctx context.Context, arg database.UnassignInflectionClassParams) error
+	CreateInflectionOverride(ctx context.Context, arg database.CreateInflectionOverrideParams) (database.InflectionOverride, error)
+	GetInflectionOverridesOfWord(ctx context.Context, wordID uuid.UUID) ([]database.InflectionOverride, error)
+	DeleteInflectionOverrides(ctx context.Context, arg database.DeleteInflectionOverridesParams) error
+
+	// Lemmatization
+	CreateLemmaRule(ctx context.Context, arg database.CreateLemmaRuleParams) (database.LemmaRule, error)
+	GetLemmaRuleByID(ctx context.Context, id uuid.UUID) (database.LemmaRule, error)
+	GetLemmaRulesOfLanguage(ctx context.Context, languageID uuid.UUID) ([]database.LemmaRule, error)
+	DeleteLemmaRule(ctx context.Context, id uuid.UUID) error
+
+	// Corpus
+	CreateText(ctx context.Context, arg database.CreateTextParams) (database.Text, error)
+	GetTextByID(ctx context.Context, id uuid.UUID) (database.Text, error)
+	GetTextsOfLanguage(ctx context.Context, languageID uuid.UUID) ([]database.Text, error)
+	UpdateText(ctx context.Context, arg database.UpdateTextParams) (database.Text, error)
+	DeleteText(ctx context.Context, id uuid.UUID) error
+
+	// Search
+	SearchDefinitions(ctx context.Context, arg database.SearchDefinitionsParams) ([]database.SearchDefinitionsRow, error)
+	SearchWordsFuzzy(ctx context.Context, arg database.SearchWordsFuzzyParams) ([]database.SearchWordsFuzzyRow, error)
+	SearchWordsPattern(ctx context.Context, arg database.SearchWordsPatternParams) ([]database.SearchWordsPatternRow, error)
+	// Sets the similarity SearchWordsFuzzy requires of its matches, until the
+	// calling transaction ends.
+	SetSimilarityThreshold(ctx context.Context, threshold float32) error
+
+	// Archives
+	RestoreLanguage(ctx context.Context, arg database.RestoreLanguageParams) (int64, error)
+	RestoreWord(ctx context.Context, arg database.RestoreWordParams) (int64, error)
+	RestoreDefinition(ctx context.Context, arg database.RestoreDefinitionParams) (int64, error)
+	DeleteAllLanguages(ctx context.Context) error
+	// Counts the rows of the tables that archives do not hold, but that
+	// deleting every language would empty.
+	CountUnarchivedRows(ctx context.Context) (database.CountUnarchivedRowsRow, error)
+}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
 	"vastestsea/internal/database"
+	"vastestsea/internal/store"
 
 	"github.com/google/uuid"
 )
@@ -15,26 +17,39 @@ import (
  * Language Handlers
  */
 
-// Get all languages
+// Get a page of languages
 func (cfg *apiConfig) getLanguages(w http.ResponseWriter, r *http.Request) {
-	languages, err := cfg.queries.GetLanguages(r.Context())
+	// The same URL serves JSON or HTML depending on Accept.
+	w.Header().Add("Vary", "Accept")
+
+	params, err := getPageParams(r)
 	if err != nil {
-		respondError("No languages found", w, http.StatusNotFound)
+		respondBrowseError(err.Error(), w, r, http.StatusBadRequest)
 		return
 	}
 
-	marshallableLanguages := []Language{}
-	for _, language := range languages {
-		marshallableLanguages = append(marshallableLanguages, getMarshallableLanguage(language))
+	languages, err := cfg.listLanguages(r.Context(), params)
+	if err != nil {
+		respondBrowseError("No languages found", w, r, http.StatusNotFound)
+		return
 	}
 
-	writeResponse(marshallableLanguages, w, http.StatusOK)
+	page := getPage(languages, params, languageCursor, getMarshallableLanguage)
+	if wantsHTML(r) {
+		renderBrowsePage(w, "languages", struct {
+			Languages []Language
+			Next      string
+		}{page.Data, nextPageLink(r, page.Next)})
+		return
+	}
+
+	writeResponse(page, w, http.StatusOK)
 }
 
 // Get the language specified in the path parameter
 func (cfg *apiConfig) getLanguage(w http.ResponseWriter, r *http.Request) {
 	languageName := r.PathValue("language")
-	language, err := cfg.queries.GetLanguage(r.Context(), strings.ToLower(languageName))
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
 	if err != nil {
 		respondError("Language not found", w, http.StatusNotFound)
 		return
@@ -62,7 +77,7 @@ func (cfg *apiConfig) createLanguage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	language, err := cfg.queries.CreateLanguage(r.Context(), params.Name)
+	language, err := cfg.store.CreateLanguage(r.Context(), params.Name)
 	if err != nil {
 		respondError(
 			fmt.Sprintf("Failed to create language: %s", err),
@@ -93,12 +108,12 @@ func (cfg *apiConfig) updateLanguage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	language, err := cfg.queries.UpdateLanguageName(r.Context(), database.UpdateLanguageNameParams{
+	language, err := cfg.store.UpdateLanguageName(r.Context(), database.UpdateLanguageNameParams{
 		Name:   params.Name,
 		Name_2: languageName,
 	})
 	if err != nil {
-		language, err = cfg.queries.CreateLanguage(r.Context(), params.Name)
+		language, err = cfg.store.CreateLanguage(r.Context(), params.Name)
 		if err != nil {
 			respondError(
 				fmt.Sprintf("Failed to create language: %s", err),
@@ -129,7 +144,7 @@ func (cfg *apiConfig) deleteLanguage(w http.ResponseWriter, r *http.Request) {
 		)
 	}
 
-	err := cfg.queries.DeleteLanguage(r.Context(), params.ID)
+	err := cfg.store.DeleteLanguage(r.Context(), params.ID)
 	if err != nil {
 		respondError(
 			fmt.Sprintf("Could not delete language: %s", err),
@@ -137,6 +152,7 @@ func (cfg *apiConfig) deleteLanguage(w http.ResponseWriter, r *http.Request) {
 			http.StatusNotFound,
 		)
 	}
+	cfg.tokenCounts.invalidate(params.ID)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -145,75 +161,133 @@ func (cfg *apiConfig) deleteLanguage(w http.ResponseWriter, r *http.Request) {
  * Word Handlers
  */
 
-// Get all words registered with a given language, as given in the path parameter
+// Get a page of the words registered with a given language, as given in the
+// path parameter. With `include=frequency`, words that occur in the
+// language's texts carry their frequency rank.
 func (cfg *apiConfig) getWordsFromLanguage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept")
+
 	languageName := r.PathValue("language")
-	language, err := cfg.queries.GetLanguage(r.Context(), strings.ToLower(languageName))
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
 	if err != nil {
-		respondError("Language not found", w, http.StatusNotFound)
+		respondBrowseError("Language not found", w, r, http.StatusNotFound)
 		return
 	}
 
-	words, err := cfg.queries.GetWordsByLanguageID(r.Context(), language.ID)
+	params, err := getPageParams(r)
 	if err != nil {
-		respondError("No words found", w, http.StatusNotFound)
+		respondBrowseError(err.Error(), w, r, http.StatusBadRequest)
 		return
 	}
 
-	marshallableWords := []Word{}
-	for _, word := range words {
-		marshallableWords = append(marshallableWords, getMarshallableWord(word, []database.Definition{}))
+	words, err := cfg.listWords(r.Context(), params, uuid.NullUUID{UUID: language.ID, Valid: true})
+	if err != nil {
+		respondBrowseError("No words found", w, r, http.StatusNotFound)
+		return
 	}
 
-	writeResponse(marshallableWords, w, http.StatusOK)
+	ranks := cfg.getRequestedFrequencyRanks(r, language.ID)
+
+	page := getPage(words, params, wordCursor, func(word database.Word) Word {
+		marshallable := getMarshallableWord(word, []database.Definition{})
+		marshallable.FrequencyRank = ranks[word.ID]
+		return marshallable
+	})
+	if wantsHTML(r) {
+		renderBrowsePage(w, "words", struct {
+			Language Language
+			Words    []Word
+			Next     string
+		}{getMarshallableLanguage(language), page.Data, nextPageLink(r, page.Next)})
+		return
+	}
+
+	writeResponse(page, w, http.StatusOK)
 }
 
 // Get a specific word, as registered in a specific language.
 // Both the word and language should be provided in the path parameters.
+// `include=frequency` adds the word's frequency rank, as for a listing.
 func (cfg *apiConfig) getWordFromLanguage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept")
+
 	languageName := r.PathValue("language")
-	language, err := cfg.queries.GetLanguage(r.Context(), strings.ToLower(languageName))
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
 	if err != nil {
-		respondError("Language not found", w, http.StatusNotFound)
+		respondBrowseError("Language not found", w, r, http.StatusNotFound)
 		return
 	}
 
 	wordName := r.PathValue("word")
-	word, err := cfg.queries.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+	word, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
 		Word:       strings.ToLower(wordName),
 		LanguageID: language.ID,
 	})
 	if err != nil {
-		respondError("Word not found", w, http.StatusNotFound)
+		respondBrowseError("Word not found", w, r, http.StatusNotFound)
 		return
 	}
 
-	definitions, _ := cfg.queries.GetDefinitionsOfWord(r.Context(), word.ID)
+	definitions, _ := cfg.store.GetDefinitionsOfWord(r.Context(), word.ID)
+	examples, _ := cfg.store.GetExamplesOfWord(r.Context(), word.ID)
+
+	ranks := cfg.getRequestedFrequencyRanks(r, language.ID)
+
+	marshallable := getMarshallableWord(word, definitions)
+	marshallable.FrequencyRank = ranks[word.ID]
+	if len(examples) > 0 {
+		marshallable.Examples = getMarshallableExamples(examples)
+	}
+
+	if wantsHTML(r) {
+		renderBrowsePage(w, "word", struct {
+			Language Language
+			Word     Word
+		}{getMarshallableLanguage(language), marshallable})
+		return
+	}
 
-	writeResponse(getMarshallableWord(word, definitions), w, http.StatusOK)
+	writeResponse(marshallable, w, http.StatusOK)
 }
 
-// Get all words registered to any language.
+// Get a page of the words registered to any language, with their definitions.
 func (cfg *apiConfig) getWords(w http.ResponseWriter, r *http.Request) {
-	words, err := cfg.queries.GetWords(r.Context())
+	params, err := getPageParams(r)
+	if err != nil {
+		respondError(err.Error(), w, http.StatusBadRequest)
+		return
+	}
+
+	words, err := cfg.listWords(r.Context(), params, uuid.NullUUID{})
 	if err != nil {
 		log.Println(err.Error())
 		respondError("No words found", w, http.StatusNotFound)
 		return
 	}
 
-	marshallableWords := []Word{}
+	// Fetch the definitions for the whole page at once, rather than per word.
+	wordIDs := []uuid.UUID{}
 	for _, word := range words {
-		marshallableWord := getMarshallableWord(word, []database.Definition{})
-
-		if definitions, err := cfg.queries.GetDefinitionsOfWord(r.Context(), word.ID); err != nil {
-			marshallableWord.Definitions = getMarshallableDefinitions(definitions)
-		}
+		wordIDs = append(wordIDs, word.ID)
+	}
+	definitions, err := cfg.store.GetDefinitionsOfWords(r.Context(), wordIDs)
+	if err != nil {
+		respondError("Failed to retrieve definitions", w, http.StatusInternalServerError)
+		return
+	}
 
-		marshallableWords = append(marshallableWords, marshallableWord)
+	definitionsByWord := map[uuid.UUID][]database.Definition{}
+	for _, definition := range definitions {
+		definitionsByWord[definition.WordID] = append(definitionsByWord[definition.WordID], definition)
 	}
 
-	writeResponse(marshallableWords, w, http.StatusOK)
+	writeResponse(
+		getPage(words, params, wordCursor, func(word database.Word) Word {
+			return getMarshallableWord(word, definitionsByWord[word.ID])
+		}),
+		w,
+		http.StatusOK,
+	)
 }
 
 // Get all possible values of a given word.
@@ -223,7 +297,7 @@ func (cfg *apiConfig) getWords(w http.ResponseWriter, r *http.Request) {
 func (cfg *apiConfig) getWord(w http.ResponseWriter, r *http.Request) {
 	wordName := r.PathValue("word")
 
-	words, err := cfg.queries.GetWord(r.Context(), strings.ToLower(wordName))
+	words, err := cfg.store.GetWord(r.Context(), strings.ToLower(wordName))
 	if err != nil {
 		respondError("No word found", w, http.StatusNotFound)
 		return
@@ -231,7 +305,7 @@ func (cfg *apiConfig) getWord(w http.ResponseWriter, r *http.Request) {
 
 	marshallableWords := []Word{}
 	for _, word := range words {
-		definitions, _ := cfg.queries.GetDefinitionsOfWord(r.Context(), word.ID)
+		definitions, _ := cfg.store.GetDefinitionsOfWord(r.Context(), word.ID)
 
 		marshallableWords = append(marshallableWords, getMarshallableWord(word, definitions))
 	}
@@ -242,11 +316,13 @@ func (cfg *apiConfig) getWord(w http.ResponseWriter, r *http.Request) {
 // Create a new word.
 // The word itself, and the language of origin, should be provided in the
 // request body. If `.language` does not exist in the database, this handler
-// creates it, and then creates the word.
+// creates it, and then creates the word. If `.validate` is set, the word must
+// fit the phonotactics of the language's phonology.
 func (cfg *apiConfig) createWord(w http.ResponseWriter, r *http.Request) {
 	type reqParams struct {
 		Word     string `json:"word"`
 		Language string `json:"language"`
+		Validate bool   `json:"validate"`
 	}
 
 	params := reqParams{}
@@ -261,29 +337,38 @@ func (cfg *apiConfig) createWord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	language, err := cfg.queries.GetLanguage(r.Context(), strings.ToLower(params.Language))
-	if err != nil {
-		language, err = cfg.queries.CreateLanguage(r.Context(), params.Language)
+	// Creating the language and the word happen atomically, so that a failure
+	// to create the word doesn't leave behind an empty language.
+	var word database.Word
+	err := cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		language, err := tx.GetLanguage(r.Context(), strings.ToLower(params.Language))
 		if err != nil {
-			respondError(
-				fmt.Sprintf("Failed to create language: %s", err),
-				w,
-				getFailedCreationCode(err),
-			)
-			return
+			language, err = tx.CreateLanguage(r.Context(), params.Language)
+			if err != nil {
+				return stepError("create language", err, getFailedCreationCode(err))
+			}
 		}
-	}
 
-	word, err := cfg.queries.CreateWord(r.Context(), database.CreateWordParams{
-		Word:       params.Word,
-		LanguageID: language.ID,
+		if params.Validate {
+			if err := validateHeadword(r.Context(), tx, language.ID, params.Word); err != nil {
+				return err
+			}
+		}
+
+		word, err = tx.CreateWord(r.Context(), database.CreateWordParams{
+			Word:       params.Word,
+			LanguageID: language.ID,
+		})
+		if err != nil {
+			return stepError("create word", err, getFailedCreationCode(err))
+		}
+
+		return nil
 	})
 	if err != nil {
-		respondError(
-			fmt.Sprintf("Failed to create word: %s", err),
-			w,
-			getFailedCreationCode(err),
-		)
+		if !respondHeadwordError(err, w) {
+			respondTxError(err, w)
+		}
 		return
 	}
 
@@ -291,17 +376,20 @@ func (cfg *apiConfig) createWord(w http.ResponseWriter, r *http.Request) {
 }
 
 // Create a word for a given language.
-// The language should be a path parameter. The word should be provided in the body.
+// The language should be a path parameter. The word should be provided in the
+// body, along with `validate` if it should be checked against the language's
+// phonotactics.
 func (cfg *apiConfig) createWordForLanguage(w http.ResponseWriter, r *http.Request) {
 	languageName := r.PathValue("language")
-	language, err := cfg.queries.GetLanguage(r.Context(), languageName)
+	language, err := cfg.store.GetLanguage(r.Context(), languageName)
 	if err != nil {
 		respondError("Language not found", w, http.StatusNotFound)
 		return
 	}
 
 	type reqParams struct {
-		Word string `json:"word"`
+		Word     string `json:"word"`
+		Validate bool   `json:"validate"`
 	}
 
 	params := reqParams{}
@@ -316,7 +404,16 @@ func (cfg *apiConfig) createWordForLanguage(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	word, err := cfg.queries.CreateWord(r.Context(), database.CreateWordParams{
+	if params.Validate {
+		if err := validateHeadword(r.Context(), cfg.store, language.ID, params.Word); err != nil {
+			if !respondHeadwordError(err, w) {
+				respondError(fmt.Sprintf("Failed to validate word: %s", err), w, http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+
+	word, err := cfg.store.CreateWord(r.Context(), database.CreateWordParams{
 		Word:       params.Word,
 		LanguageID: language.ID,
 	})
@@ -332,32 +429,20 @@ func (cfg *apiConfig) createWordForLanguage(w http.ResponseWriter, r *http.Reque
 	writeResponse(getMarshallableWord(word, []database.Definition{}), w, http.StatusCreated)
 }
 
+// Create or update a word, optionally deleting and/or adding a definition.
+// All of the resulting writes are applied in a single transaction.
+//
+// The language and word in the path are matched case-insensitively, as in
+// the read endpoints. A `delete_id` naming a definition of another word is
+// refused with a 404, rather than deleting that definition.
 func (cfg *apiConfig) updateWord(w http.ResponseWriter, r *http.Request) {
 	languageName := r.PathValue("language")
-	language, err := cfg.queries.GetLanguage(r.Context(), languageName)
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
 	if err != nil {
 		respondError("Language not found", w, http.StatusNotFound)
 		return
 	}
 
-	isNewWord := false
-	wordName := r.PathValue("word")
-	word, err := cfg.queries.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
-		Word:       wordName,
-		LanguageID: language.ID,
-	})
-	if err != nil {
-		word, err = cfg.queries.CreateWord(r.Context(), database.CreateWordParams{
-			Word:       wordName,
-			LanguageID: language.ID,
-		})
-		if err != nil {
-			respondError("Could not create word", w, http.StatusInternalServerError)
-			return
-		}
-		isNewWord = true
-	}
-
 	type reqParams struct {
 		Word       string `json:"word"`
 		Formatted  string `json:"formatted"`
@@ -377,47 +462,74 @@ func (cfg *apiConfig) updateWord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if params.Definition.DeleteID != uuid.Nil {
-		err = cfg.queries.DeleteDefinition(r.Context(), params.Definition.DeleteID)
+	isNewWord := false
+	wordName := r.PathValue("word")
+	var word database.Word
+	var definitions []database.Definition
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		var err error
+		word, err = tx.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+			Word:       strings.ToLower(wordName),
+			LanguageID: language.ID,
+		})
 		if err != nil {
-			respondError("Could not delete definition", w, http.StatusInternalServerError)
-			return
+			word, err = tx.CreateWord(r.Context(), database.CreateWordParams{
+				Word:       wordName,
+				LanguageID: language.ID,
+			})
+			if err != nil {
+				return stepError("create word", err, getFailedCreationCode(err))
+			}
+			isNewWord = true
 		}
-	}
 
-	if fmt.Sprintf("%v", params.Definition.Add) != "{ }" {
-		_, err = cfg.queries.CreateDefinition(r.Context(), database.CreateDefinitionParams{
-			WordID:       word.ID,
-			Content:      params.Definition.Add.Content,
-			PartOfSpeech: params.Definition.Add.PartOfSpeech,
-		})
+		if params.Definition.DeleteID != uuid.Nil {
+			definition, err := tx.GetDefinitionByID(r.Context(), params.Definition.DeleteID)
+			if err != nil || definition.WordID != word.ID {
+				return stepError("delete definition", errors.New("definition not found"), http.StatusNotFound)
+			}
+			if err := tx.DeleteDefinition(r.Context(), definition.ID); err != nil {
+				return stepError("delete definition", err, http.StatusInternalServerError)
+			}
+		}
 
+		if fmt.Sprintf("%v", params.Definition.Add) != "{ }" {
+			_, err = tx.CreateDefinition(r.Context(), database.CreateDefinitionParams{
+				WordID:       word.ID,
+				Content:      params.Definition.Add.Content,
+				PartOfSpeech: params.Definition.Add.PartOfSpeech,
+			})
+			if err != nil {
+				return stepError("create definition", err, getFailedCreationCode(err))
+			}
+		}
+
+		updateParams := database.UpdateWordParams{
+			ID: word.ID,
+		}
+		if params.Word != "" {
+			updateParams.Word = params.Word
+			updateParams.SetWord = true
+		}
+		if params.Formatted != "" {
+			updateParams.Formatted = params.Formatted
+			updateParams.SetFormatted = true
+		}
+
+		word, err = tx.UpdateWord(r.Context(), updateParams)
 		if err != nil {
-			respondError("Failed to create definition", w, http.StatusInternalServerError)
-			return
+			return stepError("update word", err, getFailedCreationCode(err))
 		}
-	}
 
-	updateParams := database.UpdateWordParams{
-		ID: word.ID,
-	}
-	if params.Word != "" {
-		updateParams.Word = params.Word
-		updateParams.SetWord = true
-	}
-	if params.Formatted != "" {
-		updateParams.Formatted = params.Formatted
-		updateParams.SetFormatted = true
-	}
+		definitions, err = tx.GetDefinitionsOfWord(r.Context(), word.ID)
+		if err != nil {
+			return stepError("retrieve definitions after update", err, http.StatusInternalServerError)
+		}
 
-	word, err = cfg.queries.UpdateWord(r.Context(), updateParams)
-	if err != nil {
-		respondError("Failed to update word", w, http.StatusInternalServerError)
-		return
-	}
-	definitions, err := cfg.queries.GetDefinitionsOfWord(r.Context(), word.ID)
+		return nil
+	})
 	if err != nil {
-		respondError("Failed to retrieve definitions after update", w, http.StatusInternalServerError)
+		respondTxError(err, w)
 		return
 	}
 
@@ -432,14 +544,14 @@ func (cfg *apiConfig) updateWord(w http.ResponseWriter, r *http.Request) {
 
 func (cfg *apiConfig) deleteWordFromLanguage(w http.ResponseWriter, r *http.Request) {
 	languageName := r.PathValue("language")
-	language, err := cfg.queries.GetLanguage(r.Context(), languageName)
+	language, err := cfg.store.GetLanguage(r.Context(), languageName)
 	if err != nil {
 		respondError("Language not found", w, http.StatusNotFound)
 		return
 	}
 
 	wordName := r.PathValue("word")
-	word, err := cfg.queries.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+	word, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
 		Word:       wordName,
 		LanguageID: language.ID,
 	})
@@ -448,7 +560,7 @@ func (cfg *apiConfig) deleteWordFromLanguage(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	err = cfg.queries.DeleteWord(r.Context(), word.ID)
+	err = cfg.store.DeleteWord(r.Context(), word.ID)
 	if err != nil {
 		respondError("Failed to delete word", w, http.StatusInternalServerError)
 		return
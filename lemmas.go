@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/inflection"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Lemmatization Handlers
+ */
+
+// A word an inflected form was traced back to, and the grammatical features
+// of the affixes stripped to reach it.
+type LemmaMatch struct {
+	Word     Word     `json:"word"`
+	Features []string `json:"features"`
+}
+
+// Get the affix stripping rules of the language in the path parameter.
+func (cfg *apiConfig) getLemmaRules(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	rules, err := cfg.store.GetLemmaRulesOfLanguage(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve lemma rules", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(getMarshallableLemmaRules(rules), w, http.StatusOK)
+}
+
+// Create one or more affix stripping rules for the language in the path
+// parameter. The body may be a single rule or an array of them, which are
+// created in a single transaction.
+func (cfg *apiConfig) createLemmaRules(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	params, err := decodeOneOrMany[inflection.StripRule](r.Body)
+	if err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if len(params) == 0 {
+		respondError("Invalid request body", w, http.StatusBadRequest)
+		return
+	}
+	for i, p := range params {
+		if err := p.Check(); err != nil {
+			respondError(fmt.Sprintf("Invalid rule %d: %s", i, err), w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	created := []database.LemmaRule{}
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		for i, p := range params {
+			rule, err := tx.CreateLemmaRule(r.Context(), database.CreateLemmaRuleParams{
+				LanguageID: language.ID,
+				Kind:       p.Kind,
+				Prefix:     p.Prefix,
+				Suffix:     p.Suffix,
+				Restore:    p.Restore,
+				Features:   p.Features,
+			})
+			if err != nil {
+				return stepError(fmt.Sprintf("create rule %d", i), err, getFailedCreationCode(err))
+			}
+			created = append(created, rule)
+		}
+		return nil
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	writeResponse(getMarshallableLemmaRules(created), w, http.StatusCreated)
+}
+
+// Delete an affix stripping rule of the language in the path parameter.
+func (cfg *apiConfig) deleteLemmaRule(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError("Invalid lemma rule ID", w, http.StatusBadRequest)
+		return
+	}
+
+	rule, err := cfg.store.GetLemmaRuleByID(r.Context(), id)
+	if err != nil || rule.LanguageID != language.ID {
+		respondError("Lemma rule not found", w, http.StatusNotFound)
+		return
+	}
+
+	if err := cfg.store.DeleteLemmaRule(r.Context(), rule.ID); err != nil {
+		respondError(fmt.Sprintf("Could not delete lemma rule: %s", err), w, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Look up an inflected form in the language in the path parameters. Affixes
+// are stripped by the language's rules to find candidate lemmas, and every
+// candidate that is a word of the language is returned with its definitions,
+// and the features implied by what was stripped. An exact match comes first.
+func (cfg *apiConfig) lookupForm(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	rules, err := cfg.store.GetLemmaRulesOfLanguage(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve lemma rules", w, http.StatusInternalServerError)
+		return
+	}
+
+	candidates := inflection.Lemmatize(r.PathValue("form"), getStripRules(rules))
+
+	forms := []string{}
+	for _, c := range candidates {
+		forms = append(forms, c.Lemma)
+	}
+	words, err := cfg.store.GetWordsFromLanguageByForms(r.Context(), database.GetWordsFromLanguageByFormsParams{
+		LanguageID: language.ID,
+		Forms:      forms,
+	})
+	if err != nil {
+		respondError("Failed to retrieve words", w, http.StatusInternalServerError)
+		return
+	}
+
+	wordIDs := []uuid.UUID{}
+	wordsByForm := map[string]database.Word{}
+	for _, word := range words {
+		wordIDs = append(wordIDs, word.ID)
+		wordsByForm[strings.ToLower(word.Word)] = word
+	}
+	definitions, err := cfg.store.GetDefinitionsOfWords(r.Context(), wordIDs)
+	if err != nil {
+		respondError("Failed to retrieve definitions", w, http.StatusInternalServerError)
+		return
+	}
+	definitionsByWord := map[uuid.UUID][]database.Definition{}
+	for _, definition := range definitions {
+		definitionsByWord[definition.WordID] = append(definitionsByWord[definition.WordID], definition)
+	}
+
+	matches := []LemmaMatch{}
+	for _, c := range candidates {
+		word, ok := wordsByForm[c.Lemma]
+		if !ok {
+			continue
+		}
+		matches = append(matches, LemmaMatch{
+			Word:     getMarshallableWord(word, definitionsByWord[word.ID]),
+			Features: c.Features,
+		})
+	}
+
+	writeResponse(matches, w, http.StatusOK)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/interlinear"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Dictionary Files
+ */
+
+// A word of a language with its definitions and examples, as written to a
+// dictionary file. Examples are keyed by the definition they illustrate, and
+// those of no particular definition by uuid.Nil.
+type lexiconEntry struct {
+	Word        database.Word
+	Definitions []database.Definition
+	Examples    map[uuid.UUID][]database.Example
+}
+
+// Gets every word of a language with its definitions and examples, in
+// alphabetical order.
+func (cfg *apiConfig) getLexicon(ctx context.Context, languageID uuid.UUID) ([]lexiconEntry, error) {
+	words, err := cfg.store.GetWordsByLanguageID(ctx, languageID)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(words, func(a, b database.Word) int {
+		return cmp.Or(
+			strings.Compare(strings.ToLower(a.Word), strings.ToLower(b.Word)),
+			strings.Compare(a.Word, b.Word),
+		)
+	})
+
+	wordIDs := []uuid.UUID{}
+	for _, word := range words {
+		wordIDs = append(wordIDs, word.ID)
+	}
+	definitions, err := cfg.store.GetDefinitionsOfWords(ctx, wordIDs)
+	if err != nil {
+		return nil, err
+	}
+	definitionsByWord := map[uuid.UUID][]database.Definition{}
+	for _, definition := range definitions {
+		definitionsByWord[definition.WordID] = append(definitionsByWord[definition.WordID], definition)
+	}
+
+	examples, err := cfg.store.GetExamplesOfLanguage(ctx, languageID)
+	if err != nil {
+		return nil, err
+	}
+	examplesByWord := map[uuid.UUID]map[uuid.UUID][]database.Example{}
+	for _, example := range examples {
+		if examplesByWord[example.WordID] == nil {
+			examplesByWord[example.WordID] = map[uuid.UUID][]database.Example{}
+		}
+		key := uuid.Nil
+		if example.DefinitionID.Valid {
+			key = example.DefinitionID.UUID
+		}
+		examplesByWord[example.WordID][key] = append(examplesByWord[example.WordID][key], example)
+	}
+
+	entries := []lexiconEntry{}
+	for _, word := range words {
+		entries = append(entries, lexiconEntry{
+			Word:        word,
+			Definitions: definitionsByWord[word.ID],
+			Examples:    examplesByWord[word.ID],
+		})
+	}
+	return entries, nil
+}
+
+// A word read from a dictionary file, independent of the file's format.
+type importedWord struct {
+	Word          string
+	FontFormatted string
+	Senses        []importedSense
+}
+
+// A sense of an imported word. Senses with no definition only carry
+// examples, which are then attached to the word alone.
+type importedSense struct {
+	PartOfSpeech string
+	Definition   string
+	Examples     []importedExample
+}
+
+type importedExample struct {
+	Source      string
+	Gloss       string
+	Translation string
+}
+
+// Finds the language words are imported into, creating it if it does not
+// exist.
+func (li *LexiconImport) getLanguage(ctx context.Context, tx store.Store, name string) (database.Language, error) {
+	language, err := tx.GetLanguage(ctx, strings.ToLower(name))
+	if err != nil {
+		language, err = tx.CreateLanguage(ctx, name)
+		if err != nil {
+			return database.Language{}, stepError("create language", err, getFailedCreationCode(err))
+		}
+	}
+	li.Language = getMarshallableLanguage(language)
+	return language, nil
+}
+
+// Writes an imported word to the language. A word that already exists is
+// kept, and only gains the definitions and examples it does not already
+// have, so that a file can be imported again after edits elsewhere.
+func (li *LexiconImport) importWord(ctx context.Context, tx store.Store, languageID uuid.UUID, imported importedWord) error {
+	word, err := tx.GetWordFromLanguage(ctx, database.GetWordFromLanguageParams{
+		Word:       strings.ToLower(imported.Word),
+		LanguageID: languageID,
+	})
+	switch {
+	case err == nil:
+		li.WordsExisting++
+	case errors.Is(err, sql.ErrNoRows):
+		word, err = tx.CreateFormattedWord(ctx, database.CreateFormattedWordParams{
+			Word:          imported.Word,
+			FontFormatted: sql.NullString{String: imported.FontFormatted, Valid: imported.FontFormatted != ""},
+			LanguageID:    languageID,
+		})
+		if err != nil {
+			return stepError(fmt.Sprintf("create word %q", imported.Word), err, getFailedCreationCode(err))
+		}
+		li.WordsCreated++
+	default:
+		return stepError(fmt.Sprintf("get word %q", imported.Word), err, http.StatusInternalServerError)
+	}
+
+	definitions, err := tx.GetDefinitionsOfWord(ctx, word.ID)
+	if err != nil {
+		return stepError(fmt.Sprintf("get definitions of %q", imported.Word), err, http.StatusInternalServerError)
+	}
+	definitionIDs := map[string]uuid.UUID{}
+	for _, definition := range definitions {
+		definitionIDs[definition.Content] = definition.ID
+	}
+
+	examples, err := tx.GetExamplesOfWord(ctx, word.ID)
+	if err != nil {
+		return stepError(fmt.Sprintf("get examples of %q", imported.Word), err, http.StatusInternalServerError)
+	}
+	sources := map[string]bool{}
+	for _, example := range examples {
+		sources[example.Source] = true
+	}
+
+	for _, sense := range imported.Senses {
+		definitionID := uuid.NullUUID{}
+		if sense.Definition != "" {
+			id, ok := definitionIDs[sense.Definition]
+			if !ok {
+				definition, err := tx.CreateDefinition(ctx, database.CreateDefinitionParams{
+					Content:      sense.Definition,
+					PartOfSpeech: sense.PartOfSpeech,
+					WordID:       word.ID,
+				})
+				if err != nil {
+					return stepError(fmt.Sprintf("create definition of %q", imported.Word), err, getFailedCreationCode(err))
+				}
+				id = definition.ID
+				definitionIDs[sense.Definition] = id
+				li.DefinitionsCreated++
+			}
+			definitionID = uuid.NullUUID{UUID: id, Valid: true}
+		}
+
+		for _, e := range sense.Examples {
+			if sources[e.Source] {
+				continue
+			}
+			sources[e.Source] = true
+
+			// Examples are kept even without an interlinear gloss, which
+			// dictionary files rarely carry, so that it can be added later.
+			gloss := e.Gloss
+			if _, err := interlinear.Align(e.Source, gloss); err != nil {
+				gloss = ""
+				li.unglossed++
+			}
+
+			_, err := tx.CreateExample(ctx, database.CreateExampleParams{
+				WordID:       word.ID,
+				DefinitionID: definitionID,
+				Source:       e.Source,
+				Gloss:        gloss,
+				Translation:  e.Translation,
+			})
+			if err != nil {
+				return stepError(fmt.Sprintf("create example of %q", imported.Word), err, getFailedCreationCode(err))
+			}
+			li.ExamplesCreated++
+		}
+	}
+
+	return nil
+}
+
+// Warnings about what was imported, to add to those of the file's format.
+func (li *LexiconImport) warnings() []string {
+	warnings := []string{}
+	if li.unglossed > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"%d examples had no interlinear gloss aligned with their text, and were imported without one",
+			li.unglossed,
+		))
+	}
+	return warnings
+}
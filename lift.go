@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode"
+	"vastestsea/internal/database"
+	"vastestsea/internal/lift"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * LIFT Handlers
+ */
+
+// The analysis writing system used when none is given.
+const defaultAnalysisWritingSystem = "en"
+
+// The vernacular writing system used for a language when none is given.
+// Constructed languages have no language code of their own, so this uses the
+// code reserved for private use, tagged with the language's name.
+func defaultVernacularWritingSystem(language database.Language) string {
+	tag := []rune{}
+	for _, r := range strings.ToLower(language.Name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			tag = append(tag, r)
+		}
+	}
+	if len(tag) == 0 {
+		return "qaa"
+	}
+	return "qaa-x-" + string(tag[:min(len(tag), 8)])
+}
+
+// Import a LIFT document into the language in the path parameter, creating
+// the language if it does not exist. Entries become words, and senses become
+// definitions, along with their examples. Words and definitions that already
+// exist are kept, so a document can be imported again after edits elsewhere.
+// The `vernacular` and `analysis` query parameters pick which writing systems
+// to read, defaulting to the first given. Everything is imported in a single
+// transaction, and the response reports what could not be mapped.
+func (cfg *apiConfig) importLift(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+
+	doc, err := lift.Decode(r.Body)
+	if err != nil {
+		respondError(fmt.Sprintf("Could not decode LIFT document: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	lexemes, report := doc.Lexicon(query.Get("vernacular"), query.Get("analysis"))
+
+	result := LiftImport{
+		Unmapped: report.Unmapped(),
+		Warnings: append([]string{}, report.Warnings...),
+	}
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		language, err := result.getLanguage(r.Context(), tx, languageName)
+		if err != nil {
+			return err
+		}
+
+		for _, lexeme := range lexemes {
+			imported := importedWord{Word: lexeme.Word, FontFormatted: lexeme.FontFormatted}
+			for _, sense := range lexeme.Senses {
+				s := importedSense{PartOfSpeech: sense.PartOfSpeech, Definition: sense.Definition}
+				for _, e := range sense.Examples {
+					s.Examples = append(s.Examples, importedExample(e))
+				}
+				imported.Senses = append(imported.Senses, s)
+			}
+
+			if err := result.importWord(r.Context(), tx, language.ID, imported); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+	result.Warnings = append(result.Warnings, result.warnings()...)
+
+	writeResponse(result, w, http.StatusOK)
+}
+
+// Export the language in the path parameter as a LIFT document. Each word
+// becomes an entry, and each definition a sense, with examples under the
+// sense they illustrate. The `vernacular` and `analysis` query parameters set
+// the writing systems to write.
+func (cfg *apiConfig) exportLift(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	vernacular := query.Get("vernacular")
+	if vernacular == "" {
+		vernacular = defaultVernacularWritingSystem(language)
+	}
+	analysis := query.Get("analysis")
+	if analysis == "" {
+		analysis = defaultAnalysisWritingSystem
+	}
+
+	entries, err := cfg.getLexicon(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve words", w, http.StatusInternalServerError)
+		return
+	}
+
+	lexemes := []lift.Lexeme{}
+	for _, entry := range entries {
+		lexeme := lift.Lexeme{
+			ID:        entry.Word.ID.String(),
+			CreatedAt: entry.Word.CreatedAt,
+			UpdatedAt: entry.Word.UpdatedAt,
+			Word:      entry.Word.Word,
+		}
+		if entry.Word.FontFormatted.Valid {
+			lexeme.FontFormatted = entry.Word.FontFormatted.String
+		}
+		for _, definition := range entry.Definitions {
+			lexeme.Senses = append(lexeme.Senses, lift.LexemeSense{
+				ID:           definition.ID.String(),
+				PartOfSpeech: definition.PartOfSpeech,
+				Definition:   definition.Content,
+				Examples:     getLiftExamples(entry.Examples[definition.ID]),
+			})
+		}
+		if unattached := entry.Examples[uuid.Nil]; len(unattached) > 0 {
+			lexeme.Senses = append(lexeme.Senses, lift.LexemeSense{Examples: getLiftExamples(unattached)})
+		}
+		lexemes = append(lexemes, lexeme)
+	}
+
+	doc := lift.FromLexicon(lexemes, vernacular, analysis, "vastestsea")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.ToLower(language.Name)+".lift"))
+	if err := lift.Encode(w, doc); err != nil {
+		log.Printf("Error writing LIFT document: %s", err)
+	}
+}
+
+func getLiftExamples(examples []database.Example) []lift.LexemeExample {
+	lexemeExamples := []lift.LexemeExample{}
+	for _, e := range examples {
+		lexemeExamples = append(lexemeExamples, lift.LexemeExample{
+			Source:      e.Source,
+			Gloss:       e.Gloss,
+			Translation: e.Translation,
+		})
+	}
+	return lexemeExamples
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 	"vastestsea/internal/auth"
-	"vastestsea/internal/database"
+	"vastestsea/internal/store"
 
 	"github.com/joho/godotenv"
 
@@ -15,9 +18,11 @@ import (
 )
 
 type apiConfig struct {
-	queries  *database.Queries
+	store    store.Store
 	auth     auth.AuthConfig
 	hostName string
+
+	tokenCounts *tokenCounts
 }
 
 func main() {
@@ -30,42 +35,57 @@ func main() {
 	godotenv.Load(".env." + env)
 	godotenv.Load()
 
-	// Setup db connection
-	dbURL := os.Getenv("DB_URL")
-	db, err := sql.Open("postgres", dbURL)
-	if err != nil {
-		log.Fatalf("Unable to establish connection to database. Exiting.")
+	// Setup storage. VS_STORE=memory runs the API without a database.
+	var dataStore store.Store
+	if os.Getenv("VS_STORE") == "memory" {
+		log.Println("Using in-memory store. Data will not persist between runs.")
+		dataStore = store.NewMemoryStore()
+	} else {
+		dbURL := os.Getenv("DB_URL")
+		db, err := sql.Open("postgres", dbURL)
+		if err != nil {
+			log.Fatalf("Unable to establish connection to database. Exiting.")
+		}
+		dataStore = store.NewPostgresStore(db)
+	}
+
+	// Session cookies are only sent over HTTPS unless COOKIE_SECURE=false,
+	// for serving the editor over plain HTTP during development.
+	secureCookies := true
+	if value := os.Getenv("COOKIE_SECURE"); value != "" {
+		secure, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid COOKIE_SECURE %q. Exiting.", value)
+		}
+		secureCookies = secure
 	}
-	dbQueries := database.New(db)
 
 	apiCfg := apiConfig{
-		queries: dbQueries,
+		store: dataStore,
 		auth: auth.AuthConfig{
-			ApiKey: os.Getenv("API_KEY"),
+			ApiKey:         os.Getenv("API_KEY"),
+			EditorPassword: os.Getenv("EDITOR_PASSWORD"),
+			Sessions:       auth.NewSessionStore(12*time.Hour, secureCookies),
 		},
-		hostName: os.Getenv("HOSTNAME"),
+		hostName:    os.Getenv("HOSTNAME"),
+		tokenCounts: newTokenCounts(),
 	}
 
-	// Construct mux
-	serveMux := http.NewServeMux()
-	serveMux.HandleFunc("GET /vs/languages", apiCfg.getLanguages)
-	serveMux.HandleFunc("GET /vs/languages/{language}", apiCfg.getLanguage)
-	serveMux.HandleFunc("GET /vs/languages/{language}/words", apiCfg.getWordsFromLanguage)
-	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}", apiCfg.getWordFromLanguage)
-	serveMux.HandleFunc("GET /vs/languages/words", apiCfg.getWords)
-	serveMux.HandleFunc(
-		fmt.Sprintf("GET %s/vs/languages/words/{word}", apiCfg.hostName),
-		apiCfg.getWord,
-	)
+	// Commands run against the same store instead of serving the API
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "sitegen":
+			if err := apiCfg.runSitegen(context.Background(), os.Args[2:]); err != nil {
+				log.Fatalf("sitegen: %s", err)
+			}
+		default:
+			log.Fatalf("Unknown command %q. Run without arguments to serve the API.", os.Args[1])
+		}
+		return
+	}
 
-	// Authenticated endpoints
-	serveMux.Handle("POST /vs/languages", apiCfg.getAuthenticatedHandler(apiCfg.createLanguage))
-	serveMux.Handle("DELETE /vs/languages", apiCfg.getAuthenticatedHandler(apiCfg.deleteLanguage))
-	serveMux.Handle("PUT /vs/languages/{language}", apiCfg.getAuthenticatedHandler(apiCfg.updateLanguage))
-	serveMux.Handle("POST /vs/languages/{language}/words", apiCfg.getAuthenticatedHandler(apiCfg.createWordForLanguage))
-	serveMux.Handle("PUT /vs/languages/{language}/words/{word}", apiCfg.getAuthenticatedHandler(apiCfg.updateWord))
-	serveMux.Handle("DELETE /vs/languages/{language}/words/{word}", apiCfg.getAuthenticatedHandler(apiCfg.deleteWordFromLanguage))
-	serveMux.Handle("POST /vs/languages/words", apiCfg.getAuthenticatedHandler(apiCfg.createWord))
+	// Construct mux
+	serveMux := apiCfg.newServeMux()
 
 	// Run server
 	server := http.Server{
@@ -74,3 +94,105 @@ func main() {
 	}
 	log.Fatal(server.ListenAndServe())
 }
+
+// Registers every endpoint of the API on a new mux. Tests serve this same mux
+// over httptest.
+func (cfg *apiConfig) newServeMux() *http.ServeMux {
+	serveMux := http.NewServeMux()
+
+	// Browse interface. These share their handlers with the API endpoints
+	// below, which render HTML for clients that accept it.
+	serveMux.HandleFunc("GET /{$}", cfg.getLanguages)
+	serveMux.HandleFunc("GET /languages/{language}", cfg.getWordsFromLanguage)
+	serveMux.HandleFunc("GET /languages/{language}/words/{word}", cfg.getWordFromLanguage)
+
+	serveMux.HandleFunc("GET /vs/languages", cfg.getLanguages)
+	serveMux.HandleFunc("GET /vs/languages/{language}", cfg.getLanguage)
+	serveMux.HandleFunc("GET /vs/languages/{language}/phonology", cfg.getPhonology)
+	serveMux.HandleFunc("GET /vs/languages/{language}/inflections", cfg.getInflectionClasses)
+	serveMux.HandleFunc("GET /vs/languages/{language}/inflections/{id}", cfg.getInflectionClass)
+	serveMux.HandleFunc("GET /vs/languages/{language}/lemma-rules", cfg.getLemmaRules)
+	serveMux.HandleFunc("GET /vs/languages/{language}/examples", cfg.getExamples)
+	serveMux.HandleFunc("GET /vs/languages/{language}/examples/{id}", cfg.getExample)
+	serveMux.HandleFunc("GET /vs/languages/{language}/texts", cfg.getTexts)
+	serveMux.HandleFunc("GET /vs/languages/{language}/texts/{id}", cfg.getText)
+	serveMux.HandleFunc("GET /vs/languages/{language}/texts/{id}/gloss", cfg.glossText)
+	serveMux.HandleFunc("GET /vs/languages/{language}/frequency", cfg.getFrequencies)
+	serveMux.HandleFunc("GET /vs/languages/{language}/lift", cfg.exportLift)
+	serveMux.HandleFunc("GET /vs/languages/{language}/sfm", cfg.exportSFM)
+	serveMux.HandleFunc("GET /vs/languages/{language}/export.csv", cfg.exportSpreadsheet)
+	serveMux.HandleFunc("GET /vs/languages/{language}/dictionary.html", cfg.exportDictionaryHTML)
+	serveMux.HandleFunc("GET /vs/languages/{language}/dictionary.tex", cfg.exportDictionaryLaTeX)
+	serveMux.HandleFunc("GET /vs/languages/{language}/lookup/{form}", cfg.lookupForm)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words", cfg.getWordsFromLanguage)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}", cfg.getWordFromLanguage)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}/definitions", cfg.getDefinitions)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}/definitions/{id}", cfg.getDefinition)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}/etymology", cfg.getEtymology)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}/descendants", cfg.getDescendants)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}/translations", cfg.getTranslationsOfWord)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}/paradigm", cfg.getParadigm)
+	serveMux.HandleFunc("GET /vs/languages/{language}/words/{word}/concordance", cfg.getConcordance)
+	serveMux.HandleFunc("GET /vs/languages/words", cfg.getWords)
+	serveMux.HandleFunc(
+		fmt.Sprintf("GET %s/vs/languages/words/{word}", cfg.hostName),
+		cfg.getWord,
+	)
+	serveMux.HandleFunc("GET /vs/translate/{fromLanguage}/{toLanguage}/{word}", cfg.translateWord)
+	serveMux.HandleFunc("POST /vs/languages/{language}/sound-changes", cfg.applySoundChanges)
+	serveMux.HandleFunc("POST /vs/languages/{language}/generate", cfg.generateWords)
+	serveMux.HandleFunc("GET /vs/search", cfg.searchDefinitions)
+	serveMux.HandleFunc("GET /vs/search/words", cfg.searchWords)
+
+	// Authenticated endpoints
+	serveMux.Handle("GET /vs/admin/export", cfg.getAuthenticatedHandler(cfg.exportArchive))
+	serveMux.Handle("POST /vs/admin/import", cfg.getAuthenticatedHandler(cfg.importArchive))
+	serveMux.Handle("POST /vs/languages", cfg.getAuthenticatedHandler(cfg.createLanguage))
+	serveMux.Handle("DELETE /vs/languages", cfg.getAuthenticatedHandler(cfg.deleteLanguage))
+	serveMux.Handle("PUT /vs/languages/{language}", cfg.getAuthenticatedHandler(cfg.updateLanguage))
+	serveMux.Handle("POST /vs/languages/{language}/fork", cfg.getAuthenticatedHandler(cfg.forkLanguage))
+	serveMux.Handle("POST /vs/languages/{language}/lift", cfg.getAuthenticatedHandler(cfg.importLift))
+	serveMux.Handle("POST /vs/languages/{language}/sfm", cfg.getAuthenticatedHandler(cfg.importSFM))
+	serveMux.Handle("POST /vs/languages/{language}/import", cfg.getAuthenticatedHandler(cfg.importSpreadsheet))
+	serveMux.Handle("PUT /vs/languages/{language}/phonology", cfg.getAuthenticatedHandler(cfg.updatePhonology))
+	serveMux.Handle("DELETE /vs/languages/{language}/phonology", cfg.getAuthenticatedHandler(cfg.deletePhonology))
+	serveMux.Handle("POST /vs/languages/{language}/inflections", cfg.getAuthenticatedHandler(cfg.createInflectionClass))
+	serveMux.Handle("PUT /vs/languages/{language}/inflections/{id}", cfg.getAuthenticatedHandler(cfg.updateInflectionClass))
+	serveMux.Handle("DELETE /vs/languages/{language}/inflections/{id}", cfg.getAuthenticatedHandler(cfg.deleteInflectionClass))
+	serveMux.Handle("POST /vs/languages/{language}/lemma-rules", cfg.getAuthenticatedHandler(cfg.createLemmaRules))
+	serveMux.Handle("DELETE /vs/languages/{language}/lemma-rules/{id}", cfg.getAuthenticatedHandler(cfg.deleteLemmaRule))
+	serveMux.Handle("POST /vs/languages/{language}/examples", cfg.getAuthenticatedHandler(cfg.createExample))
+	serveMux.Handle("PUT /vs/languages/{language}/examples/{id}", cfg.getAuthenticatedHandler(cfg.updateExample))
+	serveMux.Handle("DELETE /vs/languages/{language}/examples/{id}", cfg.getAuthenticatedHandler(cfg.deleteExample))
+	serveMux.Handle("POST /vs/languages/{language}/texts", cfg.getAuthenticatedHandler(cfg.createText))
+	serveMux.Handle("PUT /vs/languages/{language}/texts/{id}", cfg.getAuthenticatedHandler(cfg.updateText))
+	serveMux.Handle("DELETE /vs/languages/{language}/texts/{id}", cfg.getAuthenticatedHandler(cfg.deleteText))
+	serveMux.Handle("POST /vs/languages/{language}/words", cfg.getAuthenticatedHandler(cfg.createWordForLanguage))
+	serveMux.Handle("PUT /vs/languages/{language}/words/{word}", cfg.getAuthenticatedHandler(cfg.updateWord))
+	serveMux.Handle("DELETE /vs/languages/{language}/words/{word}", cfg.getAuthenticatedHandler(cfg.deleteWordFromLanguage))
+	serveMux.Handle("POST /vs/languages/words", cfg.getAuthenticatedHandler(cfg.createWord))
+	serveMux.Handle("POST /vs/languages/{language}/words/{word}/definitions", cfg.getAuthenticatedHandler(cfg.createDefinitions))
+	serveMux.Handle("PATCH /vs/languages/{language}/words/{word}/definitions/{id}", cfg.getAuthenticatedHandler(cfg.updateDefinition))
+	serveMux.Handle("DELETE /vs/languages/{language}/words/{word}/definitions/{id}", cfg.getAuthenticatedHandler(cfg.deleteDefinition))
+	serveMux.Handle("POST /vs/languages/{language}/words/{word}/etymology", cfg.getAuthenticatedHandler(cfg.createWordRelation))
+	serveMux.Handle("DELETE /vs/languages/{language}/words/{word}/etymology/{id}", cfg.getAuthenticatedHandler(cfg.deleteWordRelation))
+	serveMux.Handle("POST /vs/languages/{language}/words/{word}/translations", cfg.getAuthenticatedHandler(cfg.createTranslation))
+	serveMux.Handle("DELETE /vs/languages/{language}/words/{word}/translations/{id}", cfg.getAuthenticatedHandler(cfg.deleteTranslation))
+	serveMux.Handle("PUT /vs/languages/{language}/words/{word}/paradigm", cfg.getAuthenticatedHandler(cfg.updateParadigm))
+	serveMux.Handle("DELETE /vs/languages/{language}/words/{word}/paradigm/{id}", cfg.getAuthenticatedHandler(cfg.deleteParadigm))
+
+	// Web editor, authenticated by session
+	serveMux.HandleFunc("GET /edit/login", cfg.editorLoginPage)
+	serveMux.HandleFunc("POST /edit/login", cfg.editorLogin)
+	serveMux.Handle("POST /edit/logout", cfg.getEditorHandler(cfg.editorLogout))
+	serveMux.Handle("GET /edit/{$}", cfg.getEditorHandler(cfg.editLanguages))
+	serveMux.Handle("GET /edit/languages/{language}", cfg.getEditorHandler(cfg.editWords))
+	serveMux.Handle("POST /edit/languages/{language}/words", cfg.getEditorHandler(cfg.editorCreateWord))
+	serveMux.Handle("GET /edit/languages/{language}/words/{word}", cfg.getEditorHandler(cfg.editWord))
+	serveMux.Handle("POST /edit/languages/{language}/words/{word}", cfg.getEditorHandler(cfg.editorUpdateWord))
+	serveMux.Handle("POST /edit/languages/{language}/words/{word}/definitions", cfg.getEditorHandler(cfg.editorCreateDefinition))
+	serveMux.Handle("POST /edit/languages/{language}/words/{word}/definitions/{id}", cfg.getEditorHandler(cfg.editorUpdateDefinition))
+	serveMux.Handle("POST /edit/languages/{language}/words/{word}/definitions/{id}/delete", cfg.getEditorHandler(cfg.editorDeleteDefinition))
+
+	return serveMux
+}
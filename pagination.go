@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// Orderings supported by the paginated listing endpoints.
+const (
+	sortAlphabetical = "alphabetical"
+	sortCreatedAt    = "created_at"
+	sortUpdatedAt    = "updated_at"
+)
+
+// Response envelope for paginated listings. Next is the cursor to pass back
+// for the following page, and is omitted on the last page.
+type Page[T any] struct {
+	Data []T    `json:"data"`
+	Next string `json:"next,omitempty"`
+}
+
+// The position of the last row of a page, in the ordering it was fetched by.
+// Cursors are handed to clients as opaque, base64-encoded JSON.
+type pageCursor struct {
+	Sort string    `json:"s"`
+	Name string    `json:"n,omitempty"`
+	Time time.Time `json:"t,omitempty"`
+	ID   uuid.UUID `json:"i"`
+}
+
+type pageParams struct {
+	Limit  int32
+	Sort   string
+	Cursor pageCursor
+}
+
+// Parses the `limit`, `sort` and `cursor` query parameters. A cursor is only
+// valid for the sort order that produced it.
+func getPageParams(r *http.Request) (pageParams, error) {
+	params := pageParams{
+		Limit: defaultPageLimit,
+		Sort:  sortAlphabetical,
+	}
+
+	query := r.URL.Query()
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return pageParams{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		params.Limit = int32(n)
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		switch sort {
+		case sortAlphabetical, sortCreatedAt, sortUpdatedAt:
+			params.Sort = sort
+		default:
+			return pageParams{}, fmt.Errorf(
+				"sort must be one of %s, %s or %s",
+				sortAlphabetical,
+				sortCreatedAt,
+				sortUpdatedAt,
+			)
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		data, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return pageParams{}, errors.New("malformed cursor")
+		}
+		if err := json.Unmarshal(data, &params.Cursor); err != nil {
+			return pageParams{}, errors.New("malformed cursor")
+		}
+		if params.Cursor.Sort != params.Sort {
+			return pageParams{}, errors.New("cursor does not match sort order")
+		}
+	} else {
+		params.Cursor.Sort = params.Sort
+	}
+
+	return params, nil
+}
+
+func (c pageCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Builds a page from rows fetched with a limit of params.Limit+1. The extra
+// row, if present, only signals that there is a next page and is dropped.
+func getPage[T any, M any](
+	rows []T,
+	params pageParams,
+	cursorFor func(row T, sort string) pageCursor,
+	marshal func(T) M,
+) Page[M] {
+	page := Page[M]{
+		Data: []M{},
+	}
+
+	if len(rows) > int(params.Limit) {
+		rows = rows[:params.Limit]
+		page.Next = cursorFor(rows[len(rows)-1], params.Sort).encode()
+	}
+
+	for _, row := range rows {
+		page.Data = append(page.Data, marshal(row))
+	}
+
+	return page
+}
+
+// Fetches one page of languages, plus one extra row to detect a next page.
+func (cfg *apiConfig) listLanguages(ctx context.Context, params pageParams) ([]database.Language, error) {
+	if params.Sort == sortAlphabetical {
+		return cfg.store.ListLanguagesAlphabetical(ctx, database.ListLanguagesAlphabeticalParams{
+			AfterName:  params.Cursor.Name,
+			AfterID:    params.Cursor.ID,
+			MaxResults: params.Limit + 1,
+		})
+	}
+
+	return cfg.store.ListLanguagesByTime(ctx, database.ListLanguagesByTimeParams{
+		ByUpdated:  params.Sort == sortUpdatedAt,
+		AfterTime:  params.Cursor.Time,
+		AfterID:    params.Cursor.ID,
+		MaxResults: params.Limit + 1,
+	})
+}
+
+func languageCursor(l database.Language, sort string) pageCursor {
+	cursor := pageCursor{
+		Sort: sort,
+		ID:   l.ID,
+	}
+	switch sort {
+	case sortAlphabetical:
+		cursor.Name = strings.ToLower(l.Name)
+	case sortCreatedAt:
+		cursor.Time = l.CreatedAt
+	case sortUpdatedAt:
+		cursor.Time = l.UpdatedAt
+	}
+	return cursor
+}
+
+// Fetches one page of words, plus one extra row to detect a next page. If
+// languageID is valid, only words of that language are listed.
+func (cfg *apiConfig) listWords(
+	ctx context.Context,
+	params pageParams,
+	languageID uuid.NullUUID,
+) ([]database.Word, error) {
+	if params.Sort == sortAlphabetical {
+		return cfg.store.ListWordsAlphabetical(ctx, database.ListWordsAlphabeticalParams{
+			LanguageID: languageID,
+			AfterWord:  params.Cursor.Name,
+			AfterID:    params.Cursor.ID,
+			MaxResults: params.Limit + 1,
+		})
+	}
+
+	return cfg.store.ListWordsByTime(ctx, database.ListWordsByTimeParams{
+		LanguageID: languageID,
+		ByUpdated:  params.Sort == sortUpdatedAt,
+		AfterTime:  params.Cursor.Time,
+		AfterID:    params.Cursor.ID,
+		MaxResults: params.Limit + 1,
+	})
+}
+
+func wordCursor(w database.Word, sort string) pageCursor {
+	cursor := pageCursor{
+		Sort: sort,
+		ID:   w.ID,
+	}
+	switch sort {
+	case sortAlphabetical:
+		cursor.Name = strings.ToLower(w.Word)
+	case sortCreatedAt:
+		cursor.Time = w.CreatedAt
+	case sortUpdatedAt:
+		cursor.Time = w.UpdatedAt
+	}
+	return cursor
+}
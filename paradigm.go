@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"vastestsea/internal/database"
+	"vastestsea/internal/inflection"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Paradigm Handlers
+ */
+
+// The inflected forms of a word under one of its inflection classes.
+type Paradigm struct {
+	ClassID      uuid.UUID `json:"class_id"`
+	Class        string    `json:"class"`
+	PartOfSpeech string    `json:"part_of_speech"`
+	inflection.Table
+}
+
+// One row of a paradigm as laid out in the HTML table, with a cell for every
+// column. Cells no rule or override covers are left empty.
+type paradigmRow struct {
+	Label string
+	Cells []inflection.Cell
+}
+
+func (p Paradigm) Grid() []paradigmRow {
+	grid := []paradigmRow{}
+	for _, row := range p.Rows {
+		cells := []inflection.Cell{}
+		for _, column := range p.Columns {
+			cell, _ := p.Cell(row, column)
+			cells = append(cells, cell)
+		}
+		grid = append(grid, paradigmRow{Label: row, Cells: cells})
+	}
+	return grid
+}
+
+var paradigmTemplate = template.Must(template.New("paradigm").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Word}}: paradigm</title>
+<style>
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #999; padding: 0.25em 0.75em; }
+caption { font-weight: bold; text-align: left; }
+.irregular { font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Word}}</h1>
+{{range .Paradigms}}
+<table>
+<caption>{{.PartOfSpeech}}, class {{.Class}}</caption>
+<thead>
+<tr><th></th>{{range .Columns}}<th scope="col">{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{range .Grid}}<tr><th scope="row">{{.Label}}</th>{{range .Cells}}<td{{if .Irregular}} class="irregular" title="irregular"{{end}}>{{.Form}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+{{end}}
+</body>
+</html>
+`))
+
+// Builds the paradigm of a word under each of its inflection classes.
+func buildParadigms(ctx context.Context, s store.Store, word database.Word) ([]Paradigm, error) {
+	classes, err := s.GetInflectionClassesOfWord(ctx, word.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.GetInflectionOverridesOfWord(ctx, word.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	paradigms := []Paradigm{}
+	for _, class := range classes {
+		rules, err := s.GetInflectionRulesOfClass(ctx, class.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		classOverrides := []inflection.Override{}
+		for _, o := range overrides {
+			if o.ClassID == class.ID {
+				classOverrides = append(classOverrides, inflection.Override{
+					Row:    o.RowLabel,
+					Column: o.ColumnLabel,
+					Form:   o.Form,
+				})
+			}
+		}
+
+		paradigms = append(paradigms, Paradigm{
+			ClassID:      class.ID,
+			Class:        class.Name,
+			PartOfSpeech: class.PartOfSpeech,
+			Table:        inflection.Build(word.Word, getInflectionRules(rules), classOverrides),
+		})
+	}
+
+	return paradigms, nil
+}
+
+// Get the inflection tables of the word given in the path parameters, one
+// per inflection class the word is assigned to. Responds with an HTML page of
+// tables when asked to, and JSON otherwise.
+func (cfg *apiConfig) getParadigm(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	paradigms, err := buildParadigms(r.Context(), cfg.store, word)
+	if err != nil {
+		respondError("Failed to build paradigm", w, http.StatusInternalServerError)
+		return
+	}
+
+	if len(paradigms) == 0 {
+		respondError("Word has no inflection class", w, http.StatusNotFound)
+		return
+	}
+
+	if wantsHTML(r) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := paradigmTemplate.Execute(w, struct {
+			Word      string
+			Paradigms []Paradigm
+		}{word.Word, paradigms})
+		if err != nil {
+			log.Printf("Error rendering paradigm: %s", err)
+		}
+		return
+	}
+
+	writeResponse(paradigms, w, http.StatusOK)
+}
+
+// Assign an inflection class to the word given in the path parameters, along
+// with its irregular forms under that class. Any other class of the same part
+// of speech is unassigned, and the word's previous irregular forms for the
+// class are replaced.
+func (cfg *apiConfig) updateParadigm(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	type reqParams struct {
+		ClassID   uuid.UUID             `json:"class_id"`
+		Overrides []inflection.Override `json:"overrides"`
+	}
+
+	params := reqParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	class, err := cfg.store.GetInflectionClassByID(r.Context(), params.ClassID)
+	if err != nil || class.LanguageID != word.LanguageID {
+		respondError("Inflection class not found", w, http.StatusNotFound)
+		return
+	}
+
+	for i, o := range params.Overrides {
+		if o.Row == "" || o.Form == "" {
+			respondError(fmt.Sprintf("Invalid request body: override %d needs a row and a form", i), w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	var paradigms []Paradigm
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		assigned, err := tx.GetInflectionClassesOfWord(r.Context(), word.ID)
+		if err != nil {
+			return stepError("get inflection classes", err, http.StatusInternalServerError)
+		}
+		for _, other := range assigned {
+			if other.ID == class.ID || other.PartOfSpeech != class.PartOfSpeech {
+				continue
+			}
+			if err := unassignInflectionClass(r.Context(), tx, word.ID, other.ID); err != nil {
+				return stepError("unassign previous class", err, http.StatusInternalServerError)
+			}
+		}
+
+		err = tx.AssignInflectionClass(r.Context(), database.AssignInflectionClassParams{
+			WordID:  word.ID,
+			ClassID: class.ID,
+		})
+		if err != nil {
+			return stepError("assign inflection class", err, http.StatusInternalServerError)
+		}
+
+		err = tx.DeleteInflectionOverrides(r.Context(), database.DeleteInflectionOverridesParams{
+			WordID:  word.ID,
+			ClassID: class.ID,
+		})
+		if err != nil {
+			return stepError("delete old irregular forms", err, http.StatusInternalServerError)
+		}
+		for i, o := range params.Overrides {
+			_, err := tx.CreateInflectionOverride(r.Context(), database.CreateInflectionOverrideParams{
+				WordID:      word.ID,
+				ClassID:     class.ID,
+				RowLabel:    o.Row,
+				ColumnLabel: o.Column,
+				Form:        o.Form,
+			})
+			if err != nil {
+				return stepError(fmt.Sprintf("create irregular form %d", i), err, getFailedCreationCode(err))
+			}
+		}
+
+		paradigms, err = buildParadigms(r.Context(), tx, word)
+		return err
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	writeResponse(paradigms, w, http.StatusOK)
+}
+
+// Unassign the inflection class in the `id` path parameter from the word
+// given in the path parameters, discarding its irregular forms.
+func (cfg *apiConfig) deleteParadigm(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError("Invalid inflection class ID", w, http.StatusBadRequest)
+		return
+	}
+
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		return unassignInflectionClass(r.Context(), tx, word.ID, id)
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func unassignInflectionClass(ctx context.Context, tx store.Store, wordID, classID uuid.UUID) error {
+	err := tx.DeleteInflectionOverrides(ctx, database.DeleteInflectionOverridesParams{
+		WordID:  wordID,
+		ClassID: classID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.UnassignInflectionClass(ctx, database.UnassignInflectionClassParams{
+		WordID:  wordID,
+		ClassID: classID,
+	})
+}
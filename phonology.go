@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/phonology"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Phonology Handlers
+ */
+
+type responseValidationError struct {
+	Error    string `json:"error"`
+	Position int    `json:"position"`
+	Segment  string `json:"segment,omitempty"`
+}
+
+// Get the phonology of the language in the path parameter.
+func (cfg *apiConfig) getPhonology(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	p, err := cfg.store.GetPhonology(r.Context(), language.ID)
+	if err != nil {
+		respondError("Phonology not found", w, http.StatusNotFound)
+		return
+	}
+
+	marshallable, err := getMarshallablePhonology(p)
+	if err != nil {
+		respondError("Failed to read phonology", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(marshallable, w, http.StatusOK)
+}
+
+// Create or replace the phonology of the language in the path parameter.
+// The inventory is checked, and its syllable templates parsed, before it is
+// saved.
+func (cfg *apiConfig) updatePhonology(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	inventory := phonology.Inventory{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&inventory); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if err := inventory.Check(); err != nil {
+		respondError(fmt.Sprintf("Invalid phonology: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	params, err := getUpsertPhonologyParams(language.ID, inventory)
+	if err != nil {
+		respondError("Failed to encode phonology", w, http.StatusInternalServerError)
+		return
+	}
+
+	p, err := cfg.store.UpsertPhonology(r.Context(), params)
+	if err != nil {
+		respondError(fmt.Sprintf("Failed to save phonology: %s", err), w, http.StatusInternalServerError)
+		return
+	}
+
+	marshallable, err := getMarshallablePhonology(p)
+	if err != nil {
+		respondError("Failed to read phonology", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(marshallable, w, http.StatusOK)
+}
+
+// Delete the phonology of the language in the path parameter.
+func (cfg *apiConfig) deletePhonology(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	if err := cfg.store.DeletePhonology(r.Context(), language.ID); err != nil {
+		respondError(fmt.Sprintf("Could not delete phonology: %s", err), w, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getUpsertPhonologyParams(languageID uuid.UUID, inventory phonology.Inventory) (database.UpsertPhonologyParams, error) {
+	if inventory.Phonemes == nil {
+		inventory.Phonemes = []phonology.Phoneme{}
+	}
+	if inventory.Categories == nil {
+		inventory.Categories = map[string][]string{}
+	}
+
+	phonemes, err := json.Marshal(inventory.Phonemes)
+	if err != nil {
+		return database.UpsertPhonologyParams{}, err
+	}
+	categories, err := json.Marshal(inventory.Categories)
+	if err != nil {
+		return database.UpsertPhonologyParams{}, err
+	}
+
+	params := database.UpsertPhonologyParams{
+		LanguageID:        languageID,
+		Phonemes:          phonemes,
+		Categories:        categories,
+		SyllableTemplates: inventory.SyllableTemplates,
+		ForbiddenClusters: inventory.ForbiddenClusters,
+	}
+	if params.SyllableTemplates == nil {
+		params.SyllableTemplates = []string{}
+	}
+	if params.ForbiddenClusters == nil {
+		params.ForbiddenClusters = []string{}
+	}
+
+	return params, nil
+}
+
+// Fetches the inventory of a language. Returns sql.ErrNoRows if the language
+// has no phonology.
+func getInventory(ctx context.Context, s store.Store, languageID uuid.UUID) (phonology.Inventory, error) {
+	p, err := s.GetPhonology(ctx, languageID)
+	if err != nil {
+		return phonology.Inventory{}, err
+	}
+
+	return getInventoryFromPhonology(p)
+}
+
+// Checks a headword against the phonotactics of its language. Fails with a
+// *phonology.ValidationError if the word breaks them, or with sql.ErrNoRows
+// if the language has no phonology to check against.
+func validateHeadword(ctx context.Context, s store.Store, languageID uuid.UUID, word string) error {
+	inventory, err := getInventory(ctx, s, languageID)
+	if err != nil {
+		return err
+	}
+
+	return inventory.Validate(word)
+}
+
+// Responds to a failed headword validation, including where in the word the
+// problem was found. Returns false if err is not a validation failure, in
+// which case nothing is written.
+func respondHeadwordError(err error, w http.ResponseWriter) bool {
+	var validationErr *phonology.ValidationError
+	if errors.As(err, &validationErr) {
+		writeResponse(responseValidationError{
+			Error:    fmt.Sprintf("Word does not fit the language's phonotactics: %s", validationErr.Message),
+			Position: validationErr.Position,
+			Segment:  validationErr.Segment,
+		}, w, http.StatusUnprocessableEntity)
+		return true
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError("Language has no phonology to validate against", w, http.StatusUnprocessableEntity)
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Search Handlers
+ */
+
+const defaultSearchLimit = 50
+
+var highlightMarks = strings.NewReplacer(
+	store.HighlightStart, "<mark>",
+	store.HighlightStop, "</mark>",
+)
+
+// Turns a highlighted definition from the store into HTML. The text is
+// escaped first, so that only the <mark> tags are markup.
+func highlightHTML(highlighted string) string {
+	return highlightMarks.Replace(html.EscapeString(highlighted))
+}
+
+// Reverse dictionary lookup: find the words whose definitions match the `q`
+// query parameter, optionally restricted to a `language` and a
+// `part_of_speech`. Results are ranked, and matching terms are highlighted in
+// escaped HTML.
+func (cfg *apiConfig) searchDefinitions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		respondError("Missing search query", w, http.StatusBadRequest)
+		return
+	}
+
+	params := database.SearchDefinitionsParams{
+		Query:      q,
+		MaxResults: defaultSearchLimit,
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxPageLimit {
+			respondError(fmt.Sprintf("limit must be between 1 and %d", maxPageLimit), w, http.StatusBadRequest)
+			return
+		}
+		params.MaxResults = int32(n)
+	}
+
+	if languageName := query.Get("language"); languageName != "" {
+		language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+		if err != nil {
+			respondError("Language not found", w, http.StatusNotFound)
+			return
+		}
+		params.LanguageID = uuid.NullUUID{UUID: language.ID, Valid: true}
+	}
+
+	if partOfSpeech := query.Get("part_of_speech"); partOfSpeech != "" {
+		params.PartOfSpeech = sql.NullString{String: partOfSpeech, Valid: true}
+	}
+
+	rows, err := cfg.store.SearchDefinitions(r.Context(), params)
+	if err != nil {
+		respondError(fmt.Sprintf("Search failed: %s", err), w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(getMarshallableSearchResults(rows), w, http.StatusOK)
+}
+
+// Headword search modes.
+const (
+	wordSearchFuzzy   = "fuzzy"
+	wordSearchPattern = "pattern"
+	wordSearchPrefix  = "prefix"
+	wordSearchSuffix  = "suffix"
+)
+
+const defaultMinSimilarity = 0.3
+
+// Escapes the LIKE metacharacters in s, so that it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Converts a user-facing wildcard pattern, where * matches any run of
+// characters and ? any single character, into a LIKE pattern.
+func wildcardToLike(pattern string) string {
+	return strings.NewReplacer("*", "%", "?", "_").Replace(escapeLike(pattern))
+}
+
+// Headword search over words and their font formatted variants. The `mode`
+// query parameter selects between trigram similarity (`fuzzy`), wildcard
+// patterns such as `ka*n` or `?ar` (`pattern`), and anchored `prefix` and
+// `suffix` matching. Without a mode, queries containing wildcards are treated
+// as patterns, and anything else as fuzzy. Every result carries its
+// similarity to the query, for ranking suggestions.
+func (cfg *apiConfig) searchWords(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	q := strings.ToLower(strings.TrimSpace(query.Get("q")))
+	if q == "" {
+		respondError("Missing search query", w, http.StatusBadRequest)
+		return
+	}
+
+	mode := query.Get("mode")
+	if mode == "" {
+		if strings.ContainsAny(q, "*?") {
+			mode = wordSearchPattern
+		} else {
+			mode = wordSearchFuzzy
+		}
+	}
+
+	limit := int32(defaultSearchLimit)
+	if l := query.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxPageLimit {
+			respondError(fmt.Sprintf("limit must be between 1 and %d", maxPageLimit), w, http.StatusBadRequest)
+			return
+		}
+		limit = int32(n)
+	}
+
+	languageID := uuid.NullUUID{}
+	if languageName := query.Get("language"); languageName != "" {
+		language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+		if err != nil {
+			respondError("Language not found", w, http.StatusNotFound)
+			return
+		}
+		languageID = uuid.NullUUID{UUID: language.ID, Valid: true}
+	}
+
+	// Similarity is always measured against the literal part of the query.
+	literal := strings.NewReplacer("*", "", "?", "").Replace(q)
+
+	matches := []WordMatch{}
+	switch mode {
+	case wordSearchFuzzy:
+		minSimilarity := float32(defaultMinSimilarity)
+		if m := query.Get("min_similarity"); m != "" {
+			f, err := strconv.ParseFloat(m, 32)
+			if err != nil || f < 0 || f > 1 {
+				respondError("min_similarity must be between 0 and 1", w, http.StatusBadRequest)
+				return
+			}
+			minSimilarity = float32(f)
+		}
+
+		// The threshold is set for the transaction alone, so that the search
+		// can filter with the indexed % operator.
+		rows := []database.SearchWordsFuzzyRow{}
+		err := cfg.store.RunInSnapshot(r.Context(), func(tx store.Store) error {
+			err := tx.SetSimilarityThreshold(r.Context(), minSimilarity)
+			if err != nil {
+				return err
+			}
+			rows, err = tx.SearchWordsFuzzy(r.Context(), database.SearchWordsFuzzyParams{
+				Query:      q,
+				LanguageID: languageID,
+				MaxResults: limit,
+			})
+			return err
+		})
+		if err != nil {
+			respondError(fmt.Sprintf("Search failed: %s", err), w, http.StatusInternalServerError)
+			return
+		}
+		for _, row := range rows {
+			matches = append(matches, WordMatch{
+				Word:       getMarshallableWord(row.Word, []database.Definition{}),
+				Similarity: row.Similarity,
+			})
+		}
+
+	case wordSearchPattern, wordSearchPrefix, wordSearchSuffix:
+		var pattern string
+		switch mode {
+		case wordSearchPattern:
+			pattern = wildcardToLike(q)
+		case wordSearchPrefix:
+			pattern = escapeLike(q) + "%"
+		case wordSearchSuffix:
+			pattern = "%" + escapeLike(q)
+		}
+
+		rows, err := cfg.store.SearchWordsPattern(r.Context(), database.SearchWordsPatternParams{
+			Query:      literal,
+			LanguageID: languageID,
+			Pattern:    pattern,
+			MaxResults: limit,
+		})
+		if err != nil {
+			respondError(fmt.Sprintf("Search failed: %s", err), w, http.StatusInternalServerError)
+			return
+		}
+		for _, row := range rows {
+			matches = append(matches, WordMatch{
+				Word:       getMarshallableWord(row.Word, []database.Definition{}),
+				Similarity: row.Similarity,
+			})
+		}
+
+	default:
+		respondError(
+			fmt.Sprintf(
+				"mode must be one of %s, %s, %s or %s",
+				wordSearchFuzzy,
+				wordSearchPattern,
+				wordSearchPrefix,
+				wordSearchSuffix,
+			),
+			w,
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	writeResponse(matches, w, http.StatusOK)
+}
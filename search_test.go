@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSearchHighlightIsEscaped(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "sirë"}, nil, http.StatusCreated)
+	definition := map[string]string{"content": "a river <img src=x onerror=alert(1)>", "part_of_speech": "noun"}
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/sirë/definitions", definition, nil, http.StatusCreated)
+
+	results := []SearchResult{}
+	mustCall(t, server, "GET", "/vs/search?q=river", nil, &results, http.StatusOK)
+	if len(results) != 1 || len(results[0].Matches) != 1 {
+		t.Fatalf("got %v, want a single match", results)
+	}
+
+	want := "a <mark>river</mark> &lt;img src=x onerror=alert(1)&gt;"
+	if got := results[0].Matches[0].Highlighted; got != want {
+		t.Errorf("got highlighted %q, want %q", got, want)
+	}
+}
+
+func TestFuzzySearchHonoursMinSimilarity(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "mallon"}, nil, http.StatusCreated)
+
+	matches := []WordMatch{}
+	mustCall(t, server, "GET", "/vs/search/words?q=mellon&min_similarity=0.2", nil, &matches, http.StatusOK)
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches at 0.2, want both words", len(matches))
+	}
+	mustCall(t, server, "GET", "/vs/search/words?q=mellon&min_similarity=1", nil, &matches, http.StatusOK)
+	if len(matches) != 1 || matches[0].Word.Word != "mellon" {
+		t.Fatalf("got %+v at 1, want only mellon", matches)
+	}
+}
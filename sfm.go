@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"vastestsea/internal/sfm"
+	"vastestsea/internal/store"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * SFM Handlers
+ */
+
+// Reads the marker mapping from the query parameters, one per part of an
+// entry, falling back to the MDF markers for those not given.
+func getSFMMapping(query url.Values) (sfm.Mapping, error) {
+	mapping := sfm.Mapping{
+		Record:             query.Get("record"),
+		PartOfSpeech:       query.Get("part_of_speech"),
+		Sense:              query.Get("sense"),
+		Gloss:              query.Get("gloss"),
+		Definition:         query.Get("definition"),
+		Example:            query.Get("example"),
+		ExampleTranslation: query.Get("example_translation"),
+	}.WithDefaults()
+
+	return mapping, mapping.Check()
+}
+
+// Import a Toolbox or Shoebox dictionary in Standard Format Markers into the
+// language in the path parameter, creating the language if it does not
+// exist. Markers default to MDF's `\lx`, `\ps`, `\sn`, `\ge`, `\de`, `\xv`
+// and `\xe`, and each can be remapped with a query parameter, such as
+// `?gloss=gn`. Records that cannot be read as an entry are skipped and
+// reported, and the rest are imported in a single transaction.
+func (cfg *apiConfig) importSFM(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+
+	mapping, err := getSFMMapping(r.URL.Query())
+	if err != nil {
+		respondError(fmt.Sprintf("Invalid marker mapping: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		respondError(fmt.Sprintf("Could not read request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+	if !utf8.Valid(body) {
+		respondError("SFM file must be encoded as UTF-8", w, http.StatusBadRequest)
+		return
+	}
+
+	records, err := sfm.Parse(bytes.NewReader(body), mapping.Record)
+	if err != nil {
+		respondError(fmt.Sprintf("Could not parse SFM file: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	entries, failures, unmapped := mapping.Entries(records)
+
+	result := SFMImport{
+		Failed:   failures,
+		Unmapped: unmapped,
+	}
+	err = cfg.store.RunInTx(r.Context(), func(tx store.Store) error {
+		language, err := result.getLanguage(r.Context(), tx, languageName)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			imported := importedWord{Word: entry.Word}
+			for _, sense := range entry.Senses {
+				s := importedSense{PartOfSpeech: sense.PartOfSpeech, Definition: sense.Content()}
+				for _, e := range sense.Examples {
+					s.Examples = append(s.Examples, importedExample{Source: e.Source, Translation: e.Translation})
+				}
+				imported.Senses = append(imported.Senses, s)
+			}
+
+			if err := result.importWord(r.Context(), tx, language.ID, imported); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		respondTxError(err, w)
+		return
+	}
+	result.Warnings = result.warnings()
+
+	writeResponse(result, w, http.StatusOK)
+}
+
+// Export the language in the path parameter as an SFM dictionary, using the
+// same marker mapping as imports. Each definition is written as a
+// definition, and examples follow the sense they illustrate. Words without
+// definitions are left out, since a record without a sense could not be
+// imported again; the X-Skipped-Words header counts them.
+func (cfg *apiConfig) exportSFM(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	mapping, err := getSFMMapping(r.URL.Query())
+	if err != nil {
+		respondError(fmt.Sprintf("Invalid marker mapping: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	lexicon, err := cfg.getLexicon(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve words", w, http.StatusInternalServerError)
+		return
+	}
+
+	entries := []sfm.Entry{}
+	skipped := 0
+	for _, lexiconEntry := range lexicon {
+		if len(lexiconEntry.Definitions) == 0 {
+			skipped++
+			continue
+		}
+
+		entry := sfm.Entry{Word: lexiconEntry.Word.Word}
+		for _, definition := range lexiconEntry.Definitions {
+			sense := sfm.Sense{PartOfSpeech: definition.PartOfSpeech, Definition: definition.Content}
+			for _, e := range lexiconEntry.Examples[definition.ID] {
+				sense.Examples = append(sense.Examples, sfm.Example{Source: e.Source, Translation: e.Translation})
+			}
+			entry.Senses = append(entry.Senses, sense)
+		}
+		// MDF has no place for examples outside a sense, so those of no
+		// particular definition go with the first.
+		for _, e := range lexiconEntry.Examples[uuid.Nil] {
+			entry.Senses[0].Examples = append(entry.Senses[0].Examples, sfm.Example{Source: e.Source, Translation: e.Translation})
+		}
+		entries = append(entries, entry)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.ToLower(language.Name)+".sfm"))
+	w.Header().Set("X-Skipped-Words", strconv.Itoa(skipped))
+	if err := mapping.Write(w, entries); err != nil {
+		log.Printf("Error writing SFM file: %s", err)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"vastestsea/internal/database"
+	"vastestsea/internal/site"
+)
+
+/*
+ * Static Site Command
+ */
+
+// Runs `vastestsea sitegen`, which writes every language, or those named with
+// -language, as a static website into the directory given by -out.
+func (cfg *apiConfig) runSitegen(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sitegen", flag.ContinueOnError)
+	out := flags.String("out", "site", "directory to write the site into")
+	only := flags.String("language", "", "comma-separated names of the languages to publish, instead of all of them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	languages := []database.Language{}
+	if *only == "" {
+		all, err := cfg.store.GetLanguages(ctx)
+		if err != nil {
+			return fmt.Errorf("retrieve languages: %w", err)
+		}
+		languages = all
+	} else {
+		for _, name := range strings.Split(*only, ",") {
+			language, err := cfg.store.GetLanguage(ctx, strings.ToLower(strings.TrimSpace(name)))
+			if err != nil {
+				return fmt.Errorf("language %q not found", name)
+			}
+			languages = append(languages, language)
+		}
+	}
+
+	published := []site.Language{}
+	words := 0
+	for _, language := range languages {
+		lexicon, err := cfg.getLexicon(ctx, language.ID)
+		if err != nil {
+			return fmt.Errorf("retrieve words of %s: %w", language.Name, err)
+		}
+
+		entries := []site.Entry{}
+		for _, lexiconEntry := range lexicon {
+			entries = append(entries, site.Entry{
+				Entry: getPrintedEntry(lexiconEntry),
+				ID:    lexiconEntry.Word.ID.String(),
+			})
+		}
+		words += len(entries)
+
+		published = append(published, site.Language{
+			Name:    language.Name,
+			ID:      language.ID.String(),
+			Entries: entries,
+		})
+	}
+
+	if err := site.Write(*out, published); err != nil {
+		return err
+	}
+	log.Printf("Wrote %d languages and %d words to %s", len(published), words, *out)
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/sca"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Sound Change Handlers
+ */
+
+type SoundChangeResult struct {
+	WordID  uuid.UUID  `json:"word_id"`
+	Changed bool       `json:"changed"`
+	Result  sca.Result `json:"result"`
+}
+
+// Run a sound change rule set against every word of the language in the path
+// parameter, returning each word before and after along with the rules that
+// changed it. Nothing is written; see the sca package for the rule syntax.
+func (cfg *apiConfig) applySoundChanges(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	type reqParams struct {
+		Rules       string `json:"rules"`
+		Seed        int64  `json:"seed"`
+		ChangedOnly bool   `json:"changed_only"`
+	}
+
+	params := reqParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	rules, err := sca.Parse(params.Rules)
+	if err != nil {
+		respondError(fmt.Sprintf("Invalid rules: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	words, err := cfg.store.GetWordsByLanguageID(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve words", w, http.StatusInternalServerError)
+		return
+	}
+
+	results := []SoundChangeResult{}
+	for _, word := range words {
+		result := rules.Apply(word.Word, params.Seed)
+		changed := result.Output != result.Input
+		if params.ChangedOnly && !changed {
+			continue
+		}
+
+		results = append(results, SoundChangeResult{
+			WordID:  word.ID,
+			Changed: changed,
+			Result:  result,
+		})
+	}
+
+	writeResponse(results, w, http.StatusOK)
+}
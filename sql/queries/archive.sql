@@ -0,0 +1,43 @@
+-- name: RestoreLanguage :execrows
+INSERT INTO languages (id, created_at, updated_at, name)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO UPDATE
+SET created_at = EXCLUDED.created_at,
+    updated_at = EXCLUDED.updated_at,
+    name = EXCLUDED.name
+WHERE languages.updated_at < EXCLUDED.updated_at;
+
+-- name: RestoreWord :execrows
+INSERT INTO words (id, created_at, updated_at, word, font_formatted, language_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO UPDATE
+SET created_at = EXCLUDED.created_at,
+    updated_at = EXCLUDED.updated_at,
+    word = EXCLUDED.word,
+    font_formatted = EXCLUDED.font_formatted,
+    language_id = EXCLUDED.language_id
+WHERE words.updated_at < EXCLUDED.updated_at;
+
+-- name: RestoreDefinition :execrows
+INSERT INTO definitions (id, created_at, updated_at, content, part_of_speech, word_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO UPDATE
+SET created_at = EXCLUDED.created_at,
+    updated_at = EXCLUDED.updated_at,
+    content = EXCLUDED.content,
+    part_of_speech = EXCLUDED.part_of_speech,
+    word_id = EXCLUDED.word_id
+WHERE definitions.updated_at < EXCLUDED.updated_at;
+
+-- name: DeleteAllLanguages :exec
+DELETE FROM languages;
+
+-- name: CountUnarchivedRows :one
+SELECT
+    (SELECT count(*) FROM word_relations) AS word_relations,
+    (SELECT count(*) FROM translations) AS translations,
+    (SELECT count(*) FROM phonologies) AS phonologies,
+    (SELECT count(*) FROM inflection_classes) AS inflection_classes,
+    (SELECT count(*) FROM lemma_rules) AS lemma_rules,
+    (SELECT count(*) FROM examples) AS examples,
+    (SELECT count(*) FROM texts) AS texts;
@@ -21,22 +21,27 @@ WHERE id = $1;
 
 -- name: UpdateDefinitionPartOfSpeech :one
 UPDATE definitions
-SET part_of_speech = $1
+SET part_of_speech = $1, updated_at = NOW()
 WHERE id = $2
 RETURNING *;
 
 -- name: UpdateDefinitionContent :one
 UPDATE definitions
-SET content = $1
+SET content = $1, updated_at = NOW()
 WHERE id = $2
 RETURNING *;
 
 -- name: UpdateDefinition :one
 UPDATE definitions
-SET content = $1, part_of_speech = $2
+SET content = $1, part_of_speech = $2, updated_at = NOW()
 WHERE id = $3
 RETURNING *;
 
 -- name: DeleteDefinition :exec
 DELETE FROM definitions
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetDefinitionsOfWords :many
+SELECT * FROM definitions
+WHERE definitions.word_id = ANY(@word_ids::uuid[])
+ORDER BY word_id, part_of_speech ASC, content ASC;
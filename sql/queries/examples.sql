@@ -0,0 +1,47 @@
+-- name: CreateExample :one
+INSERT INTO examples (
+    id,
+    created_at,
+    updated_at,
+    word_id,
+    definition_id,
+    source,
+    gloss,
+    translation
+)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetExampleByID :one
+SELECT * FROM examples
+WHERE id = $1;
+
+-- name: GetExamplesOfWord :many
+SELECT * FROM examples
+WHERE word_id = $1
+ORDER BY created_at, id;
+
+-- name: GetExamplesOfLanguage :many
+SELECT examples.* FROM examples
+JOIN words ON words.id = examples.word_id
+WHERE words.language_id = $1
+ORDER BY examples.created_at, examples.id;
+
+-- name: UpdateExample :one
+UPDATE examples
+SET definition_id = $2, source = $3, gloss = $4, translation = $5, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteExample :exec
+DELETE FROM examples
+WHERE id = $1;
@@ -0,0 +1,113 @@
+-- name: CreateInflectionClass :one
+INSERT INTO inflection_classes (id, created_at, updated_at, language_id, part_of_speech, name)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetInflectionClassByID :one
+SELECT * FROM inflection_classes
+WHERE id = $1;
+
+-- name: GetInflectionClassesOfLanguage :many
+SELECT * FROM inflection_classes
+WHERE language_id = $1
+ORDER BY part_of_speech, name, id;
+
+-- name: UpdateInflectionClass :one
+UPDATE inflection_classes
+SET part_of_speech = $2, name = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteInflectionClass :exec
+DELETE FROM inflection_classes
+WHERE id = $1;
+
+-- name: CreateInflectionRule :one
+INSERT INTO inflection_rules (
+    id,
+    created_at,
+    updated_at,
+    class_id,
+    position,
+    row_label,
+    column_label,
+    strip,
+    prefix,
+    suffix
+)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetInflectionRulesOfClass :many
+SELECT * FROM inflection_rules
+WHERE class_id = $1
+ORDER BY position, id;
+
+-- name: DeleteInflectionRulesOfClass :exec
+DELETE FROM inflection_rules
+WHERE class_id = $1;
+
+-- name: AssignInflectionClass :exec
+INSERT INTO word_inflection_classes (word_id, class_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (word_id, class_id) DO NOTHING;
+
+-- name: GetInflectionClassesOfWord :many
+SELECT inflection_classes.* FROM inflection_classes
+JOIN word_inflection_classes ON word_inflection_classes.class_id = inflection_classes.id
+WHERE word_inflection_classes.word_id = $1
+ORDER BY inflection_classes.part_of_speech, inflection_classes.name, inflection_classes.id;
+
+-- name: UnassignInflectionClass :exec
+DELETE FROM word_inflection_classes
+WHERE word_id = $1 AND class_id = $2;
+
+-- name: CreateInflectionOverride :one
+INSERT INTO inflection_overrides (
+    id,
+    created_at,
+    updated_at,
+    word_id,
+    class_id,
+    row_label,
+    column_label,
+    form
+)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetInflectionOverridesOfWord :many
+SELECT * FROM inflection_overrides
+WHERE word_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteInflectionOverrides :exec
+DELETE FROM inflection_overrides
+WHERE word_id = $1 AND class_id = $2;
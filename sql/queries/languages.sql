@@ -25,6 +25,21 @@ WHERE id = $1;
 
 -- name: UpdateLanguageName :one
 UPDATE languages
-SET name = $1
+SET name = $1, updated_at = NOW()
 WHERE LOWER(name) = $2
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: ListLanguagesAlphabetical :many
+SELECT * FROM languages
+WHERE (LOWER(name), id) > (@after_name::text, @after_id::uuid)
+ORDER BY LOWER(name), id
+LIMIT @max_results;
+
+-- name: ListLanguagesByTime :many
+SELECT * FROM languages
+WHERE (
+    CASE WHEN @by_updated::bool THEN updated_at ELSE created_at END,
+    id
+) > (@after_time::timestamp, @after_id::uuid)
+ORDER BY CASE WHEN @by_updated::bool THEN updated_at ELSE created_at END, id
+LIMIT @max_results;
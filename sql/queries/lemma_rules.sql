@@ -0,0 +1,37 @@
+-- name: CreateLemmaRule :one
+INSERT INTO lemma_rules (
+    id,
+    created_at,
+    updated_at,
+    language_id,
+    kind,
+    prefix,
+    suffix,
+    restore,
+    features
+)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+RETURNING *;
+
+-- name: GetLemmaRuleByID :one
+SELECT * FROM lemma_rules
+WHERE id = $1;
+
+-- name: GetLemmaRulesOfLanguage :many
+SELECT * FROM lemma_rules
+WHERE language_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteLemmaRule :exec
+DELETE FROM lemma_rules
+WHERE id = $1;
@@ -0,0 +1,35 @@
+-- name: GetPhonology :one
+SELECT * FROM phonologies
+WHERE language_id = $1;
+
+-- name: UpsertPhonology :one
+INSERT INTO phonologies (
+    language_id,
+    created_at,
+    updated_at,
+    phonemes,
+    categories,
+    syllable_templates,
+    forbidden_clusters
+)
+VALUES (
+    $1,
+    NOW(),
+    NOW(),
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (language_id) DO UPDATE
+SET
+    updated_at = NOW(),
+    phonemes = EXCLUDED.phonemes,
+    categories = EXCLUDED.categories,
+    syllable_templates = EXCLUDED.syllable_templates,
+    forbidden_clusters = EXCLUDED.forbidden_clusters
+RETURNING *;
+
+-- name: DeletePhonology :exec
+DELETE FROM phonologies
+WHERE language_id = $1;
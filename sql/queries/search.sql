@@ -0,0 +1,54 @@
+-- name: SearchDefinitions :many
+SELECT
+    sqlc.embed(words),
+    sqlc.embed(definitions),
+    ts_rank(to_tsvector('english', definitions.content), query)::real AS rank,
+    ts_headline(
+        'english',
+        definitions.content,
+        query,
+        'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', HighlightAll=true'
+    )::text AS highlighted
+FROM definitions
+JOIN words ON words.id = definitions.word_id,
+    websearch_to_tsquery('english', @query::text) query
+WHERE to_tsvector('english', definitions.content) @@ query
+    AND (sqlc.narg(language_id)::uuid IS NULL OR words.language_id = sqlc.narg(language_id))
+    AND (sqlc.narg(part_of_speech)::text IS NULL OR definitions.part_of_speech = sqlc.narg(part_of_speech))
+ORDER BY rank DESC, LOWER(words.word), definitions.id
+LIMIT @max_results;
+
+-- name: SearchWordsFuzzy :many
+SELECT
+    sqlc.embed(words),
+    GREATEST(
+        similarity(LOWER(words.word), @query::text),
+        similarity(LOWER(COALESCE(words.font_formatted, '')), @query::text)
+    )::real AS similarity
+FROM words
+WHERE (sqlc.narg(language_id)::uuid IS NULL OR words.language_id = sqlc.narg(language_id))
+    AND (
+        LOWER(words.word) % @query::text
+        OR LOWER(words.font_formatted) % @query::text
+    )
+ORDER BY similarity DESC, LOWER(words.word), words.id
+LIMIT @max_results;
+
+-- name: SearchWordsPattern :many
+SELECT
+    sqlc.embed(words),
+    GREATEST(
+        similarity(LOWER(words.word), @query::text),
+        similarity(LOWER(COALESCE(words.font_formatted, '')), @query::text)
+    )::real AS similarity
+FROM words
+WHERE (sqlc.narg(language_id)::uuid IS NULL OR words.language_id = sqlc.narg(language_id))
+    AND (
+        LOWER(words.word) LIKE @pattern::text
+        OR LOWER(words.font_formatted) LIKE @pattern::text
+    )
+ORDER BY similarity DESC, LOWER(words.word), words.id
+LIMIT @max_results;
+
+-- name: SetSimilarityThreshold :exec
+SELECT set_config('pg_trgm.similarity_threshold', @threshold::real::text, true);
@@ -0,0 +1,30 @@
+-- name: CreateText :one
+INSERT INTO texts (id, created_at, updated_at, language_id, title, content)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetTextByID :one
+SELECT * FROM texts
+WHERE id = $1;
+
+-- name: GetTextsOfLanguage :many
+SELECT * FROM texts
+WHERE language_id = $1
+ORDER BY title, id;
+
+-- name: UpdateText :one
+UPDATE texts
+SET title = $2, content = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteText :exec
+DELETE FROM texts
+WHERE id = $1;
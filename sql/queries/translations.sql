@@ -0,0 +1,24 @@
+-- name: CreateTranslation :one
+INSERT INTO translations (id, created_at, updated_at, word_id, translation_word_id, definition_id)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetTranslationByID :one
+SELECT * FROM translations
+WHERE id = $1;
+
+-- name: GetTranslationsOfWord :many
+SELECT * FROM translations
+WHERE word_id = $1 OR translation_word_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteTranslation :exec
+DELETE FROM translations
+WHERE id = $1;
@@ -0,0 +1,44 @@
+-- name: CreateWordRelation :one
+INSERT INTO word_relations (id, created_at, updated_at, word_id, source_word_id, relation_type)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetWordRelationByID :one
+SELECT * FROM word_relations
+WHERE id = $1;
+
+-- name: GetAncestorRelations :many
+WITH RECURSIVE ancestors AS (
+    SELECT * FROM word_relations
+    WHERE word_relations.word_id = $1
+    UNION
+    SELECT word_relations.* FROM word_relations
+    JOIN ancestors ON word_relations.word_id = ancestors.source_word_id
+)
+SELECT * FROM ancestors
+ORDER BY created_at, id;
+
+-- name: GetDescendantRelations :many
+WITH RECURSIVE descendants AS (
+    SELECT * FROM word_relations
+    WHERE word_relations.source_word_id = $1
+    UNION
+    SELECT word_relations.* FROM word_relations
+    JOIN descendants ON word_relations.source_word_id = descendants.word_id
+)
+SELECT * FROM descendants
+ORDER BY created_at, id;
+
+-- name: DeleteWordRelation :exec
+DELETE FROM word_relations
+WHERE id = $1;
+
+-- name: LockWordRelations :exec
+SELECT pg_advisory_xact_lock(hashtext('word_relations'));
@@ -50,10 +50,36 @@ SET
     font_formatted = CASE WHEN @set_formatted::bool
         THEN @formatted::text
         ELSE font_formatted
-        END
+        END,
+    updated_at = NOW()
 WHERE id = @id
 RETURNING *;
 
 -- name: DeleteWord :exec
 DELETE FROM words
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ListWordsAlphabetical :many
+SELECT * FROM words
+WHERE (sqlc.narg(language_id)::uuid IS NULL OR language_id = sqlc.narg(language_id))
+    AND (LOWER(word), id) > (@after_word::text, @after_id::uuid)
+ORDER BY LOWER(word), id
+LIMIT @max_results;
+
+-- name: ListWordsByTime :many
+SELECT * FROM words
+WHERE (sqlc.narg(language_id)::uuid IS NULL OR language_id = sqlc.narg(language_id))
+    AND (
+        CASE WHEN @by_updated::bool THEN updated_at ELSE created_at END,
+        id
+    ) > (@after_time::timestamp, @after_id::uuid)
+ORDER BY CASE WHEN @by_updated::bool THEN updated_at ELSE created_at END, id
+LIMIT @max_results;
+
+-- name: GetWordsByIDs :many
+SELECT * FROM words
+WHERE id = ANY(@ids::uuid[]);
+
+-- name: GetWordsFromLanguageByForms :many
+SELECT * FROM words
+WHERE language_id = @language_id AND LOWER(word) = ANY(@forms::text[]);
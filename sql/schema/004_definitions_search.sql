@@ -0,0 +1,6 @@
+-- +goose Up
+CREATE INDEX definitions_content_search_idx ON definitions
+USING GIN (to_tsvector('english', content));
+
+-- +goose Down
+DROP INDEX definitions_content_search_idx;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX words_word_trgm_idx ON words
+USING GIN (LOWER(word) gin_trgm_ops);
+
+CREATE INDEX words_font_formatted_trgm_idx ON words
+USING GIN (LOWER(font_formatted) gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX words_font_formatted_trgm_idx;
+DROP INDEX words_word_trgm_idx;
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE word_relations (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    word_id UUID NOT NULL, -- The derived word, or reflex
+    source_word_id UUID NOT NULL, -- The word it comes from, or etymon
+    relation_type TEXT NOT NULL,
+    CONSTRAINT fk_word_id
+    FOREIGN KEY (word_id)
+    REFERENCES words(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_source_word_id
+    FOREIGN KEY (source_word_id)
+    REFERENCES words(id)
+    ON DELETE CASCADE,
+    CHECK (word_id <> source_word_id),
+    CHECK (relation_type IN ('inherited', 'derived', 'borrowed', 'compound')),
+    UNIQUE (word_id, source_word_id, relation_type)
+);
+
+CREATE INDEX ON word_relations (source_word_id);
+
+-- +goose Down
+DROP TABLE word_relations;
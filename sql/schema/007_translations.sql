@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE translations (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    word_id UUID NOT NULL,
+    translation_word_id UUID NOT NULL,
+    definition_id UUID, -- Nullable, set when the link only holds for one sense of word_id
+    CONSTRAINT fk_word_id
+    FOREIGN KEY (word_id)
+    REFERENCES words(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_translation_word_id
+    FOREIGN KEY (translation_word_id)
+    REFERENCES words(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_definition_id
+    FOREIGN KEY (definition_id)
+    REFERENCES definitions(id)
+    ON DELETE CASCADE,
+    CHECK (word_id <> translation_word_id)
+);
+
+-- Links are symmetric, so a pair of words is linked once in either direction.
+CREATE UNIQUE INDEX translations_unique_idx ON translations (
+    LEAST(word_id, translation_word_id),
+    GREATEST(word_id, translation_word_id),
+    COALESCE(definition_id, '00000000-0000-0000-0000-000000000000')
+);
+
+CREATE INDEX ON translations (translation_word_id);
+
+-- +goose Down
+DROP TABLE translations;
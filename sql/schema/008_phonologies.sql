@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE phonologies (
+    language_id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    phonemes JSONB NOT NULL DEFAULT '[]',
+    categories JSONB NOT NULL DEFAULT '{}',
+    syllable_templates TEXT[] NOT NULL DEFAULT '{}',
+    forbidden_clusters TEXT[] NOT NULL DEFAULT '{}',
+    CONSTRAINT fk_language_id
+    FOREIGN KEY (language_id)
+    REFERENCES languages(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE phonologies;
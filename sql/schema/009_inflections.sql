@@ -0,0 +1,78 @@
+-- +goose Up
+CREATE TABLE inflection_classes (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    language_id UUID NOT NULL,
+    part_of_speech TEXT NOT NULL,
+    name TEXT NOT NULL,
+    CONSTRAINT fk_language_id
+    FOREIGN KEY (language_id)
+    REFERENCES languages(id)
+    ON DELETE CASCADE,
+    UNIQUE (language_id, part_of_speech, name)
+);
+
+-- One cell of a class' paradigm: the form is built by removing `strip` from
+-- the end of the word, if present, then adding `prefix` and `suffix`.
+CREATE TABLE inflection_rules (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    class_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    row_label TEXT NOT NULL,
+    column_label TEXT NOT NULL DEFAULT '',
+    strip TEXT NOT NULL DEFAULT '',
+    prefix TEXT NOT NULL DEFAULT '',
+    suffix TEXT NOT NULL DEFAULT '',
+    CONSTRAINT fk_class_id
+    FOREIGN KEY (class_id)
+    REFERENCES inflection_classes(id)
+    ON DELETE CASCADE,
+    UNIQUE (class_id, row_label, column_label)
+);
+
+CREATE TABLE word_inflection_classes (
+    word_id UUID NOT NULL,
+    class_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (word_id, class_id),
+    CONSTRAINT fk_word_id
+    FOREIGN KEY (word_id)
+    REFERENCES words(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_class_id
+    FOREIGN KEY (class_id)
+    REFERENCES inflection_classes(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX ON word_inflection_classes (class_id);
+
+-- Irregular forms, replacing what a word's class would otherwise produce.
+CREATE TABLE inflection_overrides (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    word_id UUID NOT NULL,
+    class_id UUID NOT NULL,
+    row_label TEXT NOT NULL,
+    column_label TEXT NOT NULL DEFAULT '',
+    form TEXT NOT NULL,
+    CONSTRAINT fk_word_id
+    FOREIGN KEY (word_id)
+    REFERENCES words(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_class_id
+    FOREIGN KEY (class_id)
+    REFERENCES inflection_classes(id)
+    ON DELETE CASCADE,
+    UNIQUE (word_id, class_id, row_label, column_label)
+);
+
+-- +goose Down
+DROP TABLE inflection_overrides;
+DROP TABLE word_inflection_classes;
+DROP TABLE inflection_rules;
+DROP TABLE inflection_classes;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Affix stripping rules for finding the lemma of an inflected form. The
+-- affixes are removed, and `restore` appended to what remains, e.g. stripping
+-- the suffix "in" and restoring "ar" takes "lindin" back to "lindar".
+CREATE TABLE lemma_rules (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    language_id UUID NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('prefix', 'suffix', 'circumfix')),
+    prefix TEXT NOT NULL DEFAULT '',
+    suffix TEXT NOT NULL DEFAULT '',
+    restore TEXT NOT NULL DEFAULT '',
+    features TEXT NOT NULL,
+    CONSTRAINT fk_language_id
+    FOREIGN KEY (language_id)
+    REFERENCES languages(id)
+    ON DELETE CASCADE,
+    UNIQUE (language_id, prefix, suffix, restore, features)
+);
+
+-- +goose Down
+DROP TABLE lemma_rules;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Interlinear glossed example sentences. `source` is segmented into
+-- morphemes, and `gloss` must segment the same way.
+CREATE TABLE examples (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    word_id UUID NOT NULL,
+    definition_id UUID, -- Nullable, set when the example illustrates one sense of word_id
+    source TEXT NOT NULL,
+    gloss TEXT NOT NULL,
+    translation TEXT NOT NULL,
+    CONSTRAINT fk_word_id
+    FOREIGN KEY (word_id)
+    REFERENCES words(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_definition_id
+    FOREIGN KEY (definition_id)
+    REFERENCES definitions(id)
+    ON DELETE SET NULL
+);
+
+CREATE INDEX ON examples (word_id);
+
+-- +goose Down
+DROP TABLE examples;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE texts (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    language_id UUID NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    CONSTRAINT fk_language_id
+    FOREIGN KEY (language_id)
+    REFERENCES languages(id)
+    ON DELETE CASCADE,
+    UNIQUE (language_id, title)
+);
+
+-- +goose Down
+DROP TABLE texts;
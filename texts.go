@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/corpus"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Corpus Handlers
+ */
+
+type textParams struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Finds the words of a language that the tokens refer to, keyed by their
+// lowercased form.
+func (cfg *apiConfig) linkTokens(
+	ctx context.Context,
+	languageID uuid.UUID,
+	tokens []corpus.Token,
+) (map[string]database.Word, error) {
+	words, err := cfg.store.GetWordsFromLanguageByForms(ctx, database.GetWordsFromLanguageByFormsParams{
+		LanguageID: languageID,
+		Forms:      corpus.Forms(tokens),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	linked := map[string]database.Word{}
+	for _, word := range words {
+		linked[strings.ToLower(word.Word)] = word
+	}
+	return linked, nil
+}
+
+// Tokenizes a text and links its tokens to the lexicon.
+func (cfg *apiConfig) analyzeText(ctx context.Context, text database.Text) (TextAnalysis, error) {
+	tokens := corpus.Tokenize(text.Content)
+	linked, err := cfg.linkTokens(ctx, text.LanguageID, tokens)
+	if err != nil {
+		return TextAnalysis{}, err
+	}
+
+	analysis := TextAnalysis{
+		Text:          getMarshallableText(text),
+		TokenCount:    len(tokens),
+		Tokens:        []TextToken{},
+		UnknownTokens: []string{},
+	}
+
+	unknown := map[string]bool{}
+	for _, t := range tokens {
+		token := TextToken{Token: t}
+		if word, ok := linked[t.Form]; ok {
+			token.WordID = &word.ID
+		} else if !unknown[t.Form] {
+			unknown[t.Form] = true
+			analysis.UnknownTokens = append(analysis.UnknownTokens, t.Form)
+		}
+		analysis.Tokens = append(analysis.Tokens, token)
+	}
+
+	return analysis, nil
+}
+
+// Resolves the text identified by the `id` path parameter, ensuring it
+// belongs to the language in the `language` path parameter. Writes the
+// appropriate error response and returns false on failure.
+func (cfg *apiConfig) getTextFromPath(w http.ResponseWriter, r *http.Request) (database.Text, bool) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return database.Text{}, false
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError("Invalid text ID", w, http.StatusBadRequest)
+		return database.Text{}, false
+	}
+
+	text, err := cfg.store.GetTextByID(r.Context(), id)
+	if err != nil || text.LanguageID != language.ID {
+		respondError("Text not found", w, http.StatusNotFound)
+		return database.Text{}, false
+	}
+
+	return text, true
+}
+
+func decodeTextParams(w http.ResponseWriter, r *http.Request) (textParams, bool) {
+	params := textParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return textParams{}, false
+	}
+
+	if params.Title == "" || strings.TrimSpace(params.Content) == "" {
+		respondError("Invalid request body: title and content are required", w, http.StatusBadRequest)
+		return textParams{}, false
+	}
+
+	return params, true
+}
+
+// Get the texts of the language in the path parameter, without analysis.
+func (cfg *apiConfig) getTexts(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	texts, err := cfg.store.GetTextsOfLanguage(r.Context(), language.ID)
+	if err != nil {
+		respondError("Failed to retrieve texts", w, http.StatusInternalServerError)
+		return
+	}
+
+	marshallable := []Text{}
+	for _, text := range texts {
+		marshallable = append(marshallable, getMarshallableText(text))
+	}
+
+	writeResponse(marshallable, w, http.StatusOK)
+}
+
+// Get a text, tokenized, with each token linked to the word it matches and
+// a list of the tokens that match no word.
+func (cfg *apiConfig) getText(w http.ResponseWriter, r *http.Request) {
+	text, ok := cfg.getTextFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	analysis, err := cfg.analyzeText(r.Context(), text)
+	if err != nil {
+		respondError("Failed to link text to the lexicon", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(analysis, w, http.StatusOK)
+}
+
+// Upload a text written in the language in the path parameter. The response
+// reports its tokens, and which of them are not yet in the lexicon.
+func (cfg *apiConfig) createText(w http.ResponseWriter, r *http.Request) {
+	languageName := r.PathValue("language")
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(languageName))
+	if err != nil {
+		respondError("Language not found", w, http.StatusNotFound)
+		return
+	}
+
+	params, ok := decodeTextParams(w, r)
+	if !ok {
+		return
+	}
+
+	text, err := cfg.store.CreateText(r.Context(), database.CreateTextParams{
+		LanguageID: language.ID,
+		Title:      params.Title,
+		Content:    params.Content,
+	})
+	if err != nil {
+		respondError(fmt.Sprintf("Failed to create text: %s", err), w, getFailedCreationCode(err))
+		return
+	}
+	cfg.tokenCounts.invalidate(language.ID)
+
+	analysis, err := cfg.analyzeText(r.Context(), text)
+	if err != nil {
+		respondError("Failed to link text to the lexicon", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(analysis, w, http.StatusCreated)
+}
+
+// Replace the title and content of a text.
+func (cfg *apiConfig) updateText(w http.ResponseWriter, r *http.Request) {
+	text, ok := cfg.getTextFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params, ok := decodeTextParams(w, r)
+	if !ok {
+		return
+	}
+
+	text, err := cfg.store.UpdateText(r.Context(), database.UpdateTextParams{
+		ID:      text.ID,
+		Title:   params.Title,
+		Content: params.Content,
+	})
+	if err != nil {
+		respondError(fmt.Sprintf("Failed to update text: %s", err), w, getFailedCreationCode(err))
+		return
+	}
+	cfg.tokenCounts.invalidate(text.LanguageID)
+
+	analysis, err := cfg.analyzeText(r.Context(), text)
+	if err != nil {
+		respondError("Failed to link text to the lexicon", w, http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(analysis, w, http.StatusOK)
+}
+
+// Delete a text.
+func (cfg *apiConfig) deleteText(w http.ResponseWriter, r *http.Request) {
+	text, ok := cfg.getTextFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := cfg.store.DeleteText(r.Context(), text.ID); err != nil {
+		respondError(fmt.Sprintf("Could not delete text: %s", err), w, http.StatusInternalServerError)
+		return
+	}
+	cfg.tokenCounts.invalidate(text.LanguageID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Get an automatic interlinear gloss of a text, line by line. Each token is
+// glossed with the first definition of the word it matches, and tokens that
+// match no word are glossed as "?", to show where the lexicon falls short.
+func (cfg *apiConfig) glossText(w http.ResponseWriter, r *http.Request) {
+	text, ok := cfg.getTextFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	tokens := corpus.Tokenize(text.Content)
+	linked, err := cfg.linkTokens(r.Context(), text.LanguageID, tokens)
+	if err != nil {
+		respondError("Failed to link text to the lexicon", w, http.StatusInternalServerError)
+		return
+	}
+
+	wordIDs := []uuid.UUID{}
+	for _, word := range linked {
+		wordIDs = append(wordIDs, word.ID)
+	}
+	definitions, err := cfg.store.GetDefinitionsOfWords(r.Context(), wordIDs)
+	if err != nil {
+		respondError("Failed to retrieve definitions", w, http.StatusInternalServerError)
+		return
+	}
+	// Definitions come ordered per word, so the first seen is the first sense.
+	firstDefinitions := map[uuid.UUID]database.Definition{}
+	for _, definition := range definitions {
+		if _, ok := firstDefinitions[definition.WordID]; !ok {
+			firstDefinitions[definition.WordID] = definition
+		}
+	}
+
+	lines := strings.Split(text.Content, "\n")
+	gloss := TextGloss{
+		TextID:        text.ID,
+		Title:         text.Title,
+		Lines:         []GlossLine{},
+		UnknownTokens: []string{},
+	}
+
+	unknown := map[string]bool{}
+	known := 0
+	for _, t := range tokens {
+		for len(gloss.Lines) <= t.Line {
+			n := len(gloss.Lines)
+			gloss.Lines = append(gloss.Lines, GlossLine{
+				Line:   n,
+				Text:   strings.TrimRight(lines[n], "\r"),
+				Tokens: []GlossedToken{},
+			})
+		}
+
+		glossed := GlossedToken{Token: t.Text, Gloss: "?"}
+		if word, ok := linked[t.Form]; ok {
+			known++
+			glossed.WordID = &word.ID
+			glossed.Gloss = ""
+			if definition, ok := firstDefinitions[word.ID]; ok {
+				glossed.Gloss = definition.Content
+				glossed.PartOfSpeech = definition.PartOfSpeech
+			}
+		} else if !unknown[t.Form] {
+			unknown[t.Form] = true
+			gloss.UnknownTokens = append(gloss.UnknownTokens, t.Form)
+		}
+
+		line := &gloss.Lines[t.Line]
+		line.Tokens = append(line.Tokens, glossed)
+	}
+
+	if len(tokens) > 0 {
+		gloss.Coverage = float64(known) / float64(len(tokens))
+	}
+
+	writeResponse(gloss, w, http.StatusOK)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"vastestsea/internal/database"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Translation Handlers
+ */
+
+// Link the word in the path parameters to an equivalent word in another
+// language. Links are symmetric, so words already linked the other way can't
+// be linked again. If `definition_id` is given, the link only holds for that
+// sense of the word in the path.
+func (cfg *apiConfig) createTranslation(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	type reqParams struct {
+		Language     string    `json:"language"`
+		Word         string    `json:"word"`
+		DefinitionID uuid.UUID `json:"definition_id"`
+	}
+
+	params := reqParams{}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondError(fmt.Sprintf("Could not decode request body: %s", err), w, http.StatusBadRequest)
+		return
+	}
+
+	if params.Language == "" || params.Word == "" {
+		respondError("Invalid request body", w, http.StatusBadRequest)
+		return
+	}
+
+	language, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(params.Language))
+	if err != nil {
+		respondError("Translation language not found", w, http.StatusNotFound)
+		return
+	}
+	if language.ID == word.LanguageID {
+		respondError("Translations must link words of different languages", w, http.StatusUnprocessableEntity)
+		return
+	}
+
+	translationWord, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+		Word:       strings.ToLower(params.Word),
+		LanguageID: language.ID,
+	})
+	if err != nil {
+		respondError("Translation word not found", w, http.StatusNotFound)
+		return
+	}
+
+	definitionID := uuid.NullUUID{}
+	if params.DefinitionID != uuid.Nil {
+		definition, err := cfg.store.GetDefinitionByID(r.Context(), params.DefinitionID)
+		if err != nil || definition.WordID != word.ID {
+			respondError("Definition not found", w, http.StatusNotFound)
+			return
+		}
+		definitionID = uuid.NullUUID{UUID: definition.ID, Valid: true}
+	}
+
+	translation, err := cfg.store.CreateTranslation(r.Context(), database.CreateTranslationParams{
+		WordID:            word.ID,
+		TranslationWordID: translationWord.ID,
+		DefinitionID:      definitionID,
+	})
+	if err != nil {
+		respondError(
+			fmt.Sprintf("Failed to create translation: %s", err),
+			w,
+			getFailedCreationCode(err),
+		)
+		return
+	}
+
+	writeResponse(getMarshallableTranslation(translation), w, http.StatusCreated)
+}
+
+// Get every translation link of the word in the path parameters, in either
+// direction.
+func (cfg *apiConfig) getTranslationsOfWord(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	translations, err := cfg.store.GetTranslationsOfWord(r.Context(), word.ID)
+	if err != nil {
+		respondError("Failed to retrieve translations", w, http.StatusInternalServerError)
+		return
+	}
+
+	marshallable := []Translation{}
+	for _, translation := range translations {
+		marshallable = append(marshallable, getMarshallableTranslation(translation))
+	}
+
+	writeResponse(marshallable, w, http.StatusOK)
+}
+
+// Remove a translation link from the word in the path parameters.
+func (cfg *apiConfig) deleteTranslation(w http.ResponseWriter, r *http.Request) {
+	word, ok := cfg.getWordFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError("Invalid translation ID", w, http.StatusBadRequest)
+		return
+	}
+
+	translation, err := cfg.store.GetTranslationByID(r.Context(), id)
+	if err != nil || (translation.WordID != word.ID && translation.TranslationWordID != word.ID) {
+		respondError("Translation not found", w, http.StatusNotFound)
+		return
+	}
+
+	if err := cfg.store.DeleteTranslation(r.Context(), translation.ID); err != nil {
+		respondError("Failed to delete translation", w, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Translate a word from one language to another, returning every word of the
+// target language linked to it, with their definitions.
+func (cfg *apiConfig) translateWord(w http.ResponseWriter, r *http.Request) {
+	fromLanguage, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(r.PathValue("fromLanguage")))
+	if err != nil {
+		respondError("Source language not found", w, http.StatusNotFound)
+		return
+	}
+	toLanguage, err := cfg.store.GetLanguage(r.Context(), strings.ToLower(r.PathValue("toLanguage")))
+	if err != nil {
+		respondError("Target language not found", w, http.StatusNotFound)
+		return
+	}
+
+	word, err := cfg.store.GetWordFromLanguage(r.Context(), database.GetWordFromLanguageParams{
+		Word:       strings.ToLower(r.PathValue("word")),
+		LanguageID: fromLanguage.ID,
+	})
+	if err != nil {
+		respondError("Word not found", w, http.StatusNotFound)
+		return
+	}
+
+	translations, err := cfg.store.GetTranslationsOfWord(r.Context(), word.ID)
+	if err != nil {
+		respondError("Failed to retrieve translations", w, http.StatusInternalServerError)
+		return
+	}
+
+	// Links are stored once, so the candidate may be on either end.
+	otherIDs := []uuid.UUID{}
+	for _, translation := range translations {
+		if translation.WordID == word.ID {
+			otherIDs = append(otherIDs, translation.TranslationWordID)
+		} else {
+			otherIDs = append(otherIDs, translation.WordID)
+		}
+	}
+
+	others, err := cfg.store.GetWordsByIDs(r.Context(), otherIDs)
+	if err != nil {
+		respondError("Failed to retrieve translations", w, http.StatusInternalServerError)
+		return
+	}
+	candidates := map[uuid.UUID]database.Word{}
+	candidateIDs := []uuid.UUID{}
+	for _, other := range others {
+		if other.LanguageID == toLanguage.ID {
+			candidates[other.ID] = other
+			candidateIDs = append(candidateIDs, other.ID)
+		}
+	}
+
+	definitions, err := cfg.store.GetDefinitionsOfWords(r.Context(), append(candidateIDs, word.ID))
+	if err != nil {
+		respondError("Failed to retrieve definitions", w, http.StatusInternalServerError)
+		return
+	}
+	definitionsByWord := map[uuid.UUID][]database.Definition{}
+	definitionsByID := map[uuid.UUID]database.Definition{}
+	for _, definition := range definitions {
+		definitionsByWord[definition.WordID] = append(definitionsByWord[definition.WordID], definition)
+		definitionsByID[definition.ID] = definition
+	}
+
+	results := []TranslationCandidate{}
+	for i, translation := range translations {
+		candidate, ok := candidates[otherIDs[i]]
+		if !ok {
+			continue
+		}
+
+		result := TranslationCandidate{
+			Word:        getMarshallableWord(candidate, definitionsByWord[candidate.ID]),
+			Translation: getMarshallableTranslation(translation),
+		}
+		if translation.DefinitionID.Valid {
+			if sense, ok := definitionsByID[translation.DefinitionID.UUID]; ok {
+				marshallable := getMarshallableDefinition(sense)
+				result.Sense = &marshallable
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	writeResponse(results, w, http.StatusOK)
+}
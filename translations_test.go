@@ -0,0 +1,25 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestTranslationsAreLinkedOnceEitherWay(t *testing.T) {
+	server := newTestServer(t)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "quenya"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages", map[string]string{"name": "sindarin"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/quenya/words", map[string]string{"word": "meldo"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/sindarin/words", map[string]string{"word": "mellon"}, nil, http.StatusCreated)
+
+	mustCall(t, server, "POST", "/vs/languages/quenya/words/meldo/translations",
+		map[string]string{"language": "sindarin", "word": "mellon"}, nil, http.StatusCreated)
+	mustCall(t, server, "POST", "/vs/languages/sindarin/words/mellon/translations",
+		map[string]string{"language": "quenya", "word": "meldo"}, nil, http.StatusUnprocessableEntity)
+
+	translations := []Translation{}
+	mustCall(t, server, "GET", "/vs/languages/sindarin/words/mellon/translations", nil, &translations, http.StatusOK)
+	if len(translations) != 1 {
+		t.Errorf("got %d translations, want the one link", len(translations))
+	}
+}
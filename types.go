@@ -1,8 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"time"
+	"vastestsea/internal/corpus"
 	"vastestsea/internal/database"
+	"vastestsea/internal/inflection"
+	"vastestsea/internal/interlinear"
+	"vastestsea/internal/lift"
+	"vastestsea/internal/phonology"
+	"vastestsea/internal/sfm"
 
 	"github.com/google/uuid"
 )
@@ -32,7 +39,9 @@ type Word struct {
 	Word          string       `json:"word"`
 	FontFormatted string       `json:"font_formatted"`
 	LanguageID    uuid.UUID    `json:"language_id"`
+	FrequencyRank int          `json:"frequency_rank,omitempty"`
 	Definitions   []Definition `json:"definitions,omitempty"`
+	Examples      []Example    `json:"examples,omitempty"`
 }
 
 func getMarshallableWord(w database.Word, d []database.Definition) Word {
@@ -88,3 +97,478 @@ func getMarshallableDefinitions(definitions []database.Definition) []Definition
 
 	return marshallable
 }
+
+// Highlighted is HTML: the definition's content, escaped, with the matching
+// words wrapped in <mark> tags. It is safe to render as is.
+type SearchMatch struct {
+	Definition  Definition `json:"definition"`
+	Highlighted string     `json:"highlighted"`
+	Rank        float32    `json:"rank"`
+}
+
+type SearchResult struct {
+	Word    Word          `json:"word"`
+	Rank    float32       `json:"rank"`
+	Matches []SearchMatch `json:"matches"`
+}
+
+// Groups definition matches by word, keeping words in order of their best
+// ranked match.
+func getMarshallableSearchResults(rows []database.SearchDefinitionsRow) []SearchResult {
+	results := []SearchResult{}
+	indexes := map[uuid.UUID]int{}
+
+	for _, row := range rows {
+		i, ok := indexes[row.Word.ID]
+		if !ok {
+			i = len(results)
+			indexes[row.Word.ID] = i
+			results = append(results, SearchResult{
+				Word:    getMarshallableWord(row.Word, []database.Definition{}),
+				Rank:    row.Rank,
+				Matches: []SearchMatch{},
+			})
+		}
+
+		results[i].Matches = append(results[i].Matches, SearchMatch{
+			Definition:  getMarshallableDefinition(row.Definition),
+			Highlighted: highlightHTML(row.Highlighted),
+			Rank:        row.Rank,
+		})
+	}
+
+	return results
+}
+
+type WordMatch struct {
+	Word       Word    `json:"word"`
+	Similarity float32 `json:"similarity"`
+}
+
+type WordRelation struct {
+	ID           uuid.UUID `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	WordID       uuid.UUID `json:"word_id"`
+	SourceWordID uuid.UUID `json:"source_word_id"`
+	RelationType string    `json:"relation_type"`
+}
+
+func getMarshallableWordRelation(r database.WordRelation) WordRelation {
+	marshallable := WordRelation{
+		ID:           r.ID,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+		WordID:       r.WordID,
+		SourceWordID: r.SourceWordID,
+		RelationType: r.RelationType,
+	}
+
+	return marshallable
+}
+
+// A word in an etymology tree. The relation links it to its parent in the
+// tree, and is absent on the root. Repeated marks a word already expanded
+// elsewhere in the tree, whose branches are not shown again.
+type EtymologyNode struct {
+	Word        Word            `json:"word"`
+	Relation    *WordRelation   `json:"relation,omitempty"`
+	Repeated    bool            `json:"repeated,omitempty"`
+	Sources     []EtymologyNode `json:"sources,omitempty"`
+	Descendants []EtymologyNode `json:"descendants,omitempty"`
+}
+
+type Translation struct {
+	ID                uuid.UUID  `json:"id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	WordID            uuid.UUID  `json:"word_id"`
+	TranslationWordID uuid.UUID  `json:"translation_word_id"`
+	DefinitionID      *uuid.UUID `json:"definition_id,omitempty"`
+}
+
+func getMarshallableTranslation(t database.Translation) Translation {
+	marshallable := Translation{
+		ID:                t.ID,
+		CreatedAt:         t.CreatedAt,
+		UpdatedAt:         t.UpdatedAt,
+		WordID:            t.WordID,
+		TranslationWordID: t.TranslationWordID,
+	}
+
+	if t.DefinitionID.Valid {
+		marshallable.DefinitionID = &t.DefinitionID.UUID
+	}
+
+	return marshallable
+}
+
+// A word linked as a translation of another, along with the link itself.
+// Sense is set when the link only holds for one definition of the source word.
+type TranslationCandidate struct {
+	Word        Word        `json:"word"`
+	Translation Translation `json:"translation"`
+	Sense       *Definition `json:"sense,omitempty"`
+}
+
+// A word as copied into a forked language. WordID is only set once the fork
+// has actually been written.
+type ForkedWord struct {
+	SourceWordID uuid.UUID  `json:"source_word_id"`
+	Source       string     `json:"source"`
+	Word         string     `json:"word"`
+	WordID       *uuid.UUID `json:"word_id,omitempty"`
+	Definitions  int        `json:"definitions"`
+}
+
+type Fork struct {
+	DryRun   bool         `json:"dry_run"`
+	Language *Language    `json:"language,omitempty"`
+	Name     string       `json:"name"`
+	Words    []ForkedWord `json:"words"`
+}
+
+type Phonology struct {
+	LanguageID uuid.UUID `json:"language_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	phonology.Inventory
+}
+
+func getInventoryFromPhonology(p database.Phonology) (phonology.Inventory, error) {
+	inventory := phonology.Inventory{
+		SyllableTemplates: p.SyllableTemplates,
+		ForbiddenClusters: p.ForbiddenClusters,
+	}
+
+	if err := json.Unmarshal(p.Phonemes, &inventory.Phonemes); err != nil {
+		return phonology.Inventory{}, err
+	}
+	if err := json.Unmarshal(p.Categories, &inventory.Categories); err != nil {
+		return phonology.Inventory{}, err
+	}
+
+	return inventory, nil
+}
+
+func getMarshallablePhonology(p database.Phonology) (Phonology, error) {
+	inventory, err := getInventoryFromPhonology(p)
+	if err != nil {
+		return Phonology{}, err
+	}
+
+	marshallable := Phonology{
+		LanguageID: p.LanguageID,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+		Inventory:  inventory,
+	}
+
+	return marshallable, nil
+}
+
+type InflectionClass struct {
+	ID           uuid.UUID         `json:"id"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	LanguageID   uuid.UUID         `json:"language_id"`
+	PartOfSpeech string            `json:"part_of_speech"`
+	Name         string            `json:"name"`
+	Rules        []inflection.Rule `json:"rules"`
+}
+
+func getInflectionRules(rules []database.InflectionRule) []inflection.Rule {
+	converted := []inflection.Rule{}
+	for _, r := range rules {
+		converted = append(converted, inflection.Rule{
+			Row:    r.RowLabel,
+			Column: r.ColumnLabel,
+			Strip:  r.Strip,
+			Prefix: r.Prefix,
+			Suffix: r.Suffix,
+		})
+	}
+	return converted
+}
+
+func getMarshallableInflectionClass(c database.InflectionClass, rules []database.InflectionRule) InflectionClass {
+	marshallable := InflectionClass{
+		ID:           c.ID,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+		LanguageID:   c.LanguageID,
+		PartOfSpeech: c.PartOfSpeech,
+		Name:         c.Name,
+		Rules:        getInflectionRules(rules),
+	}
+
+	return marshallable
+}
+
+type LemmaRule struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	LanguageID uuid.UUID `json:"language_id"`
+	inflection.StripRule
+}
+
+func getStripRules(rules []database.LemmaRule) []inflection.StripRule {
+	converted := []inflection.StripRule{}
+	for _, r := range rules {
+		converted = append(converted, inflection.StripRule{
+			Kind:     r.Kind,
+			Prefix:   r.Prefix,
+			Suffix:   r.Suffix,
+			Restore:  r.Restore,
+			Features: r.Features,
+		})
+	}
+	return converted
+}
+
+func getMarshallableLemmaRules(rules []database.LemmaRule) []LemmaRule {
+	marshallable := []LemmaRule{}
+	for i, r := range getStripRules(rules) {
+		marshallable = append(marshallable, LemmaRule{
+			ID:         rules[i].ID,
+			CreatedAt:  rules[i].CreatedAt,
+			UpdatedAt:  rules[i].UpdatedAt,
+			LanguageID: rules[i].LanguageID,
+			StripRule:  r,
+		})
+	}
+	return marshallable
+}
+
+type Example struct {
+	ID           uuid.UUID  `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	WordID       uuid.UUID  `json:"word_id"`
+	DefinitionID *uuid.UUID `json:"definition_id,omitempty"`
+	Source       string     `json:"source"`
+	Gloss        string     `json:"gloss"`
+	Translation  string     `json:"translation"`
+	// The source and gloss aligned word by word and morpheme by morpheme.
+	Interlinear []interlinear.Word `json:"interlinear,omitempty"`
+}
+
+func getMarshallableExample(e database.Example) Example {
+	marshallable := Example{
+		ID:          e.ID,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+		WordID:      e.WordID,
+		Source:      e.Source,
+		Gloss:       e.Gloss,
+		Translation: e.Translation,
+	}
+
+	if e.DefinitionID.Valid {
+		marshallable.DefinitionID = &e.DefinitionID.UUID
+	}
+
+	// Examples are checked when they are written, so this only fails for
+	// rows edited outside the API.
+	if words, err := interlinear.Align(e.Source, e.Gloss); err == nil {
+		marshallable.Interlinear = words
+	}
+
+	return marshallable
+}
+
+func getMarshallableExamples(examples []database.Example) []Example {
+	marshallable := []Example{}
+	for _, e := range examples {
+		marshallable = append(marshallable, getMarshallableExample(e))
+	}
+	return marshallable
+}
+
+type Text struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	LanguageID uuid.UUID `json:"language_id"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+}
+
+func getMarshallableText(t database.Text) Text {
+	marshallable := Text{
+		ID:         t.ID,
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
+		LanguageID: t.LanguageID,
+		Title:      t.Title,
+		Content:    t.Content,
+	}
+
+	return marshallable
+}
+
+// A token of a text, and the word it matches, if any.
+type TextToken struct {
+	corpus.Token
+	WordID *uuid.UUID `json:"word_id,omitempty"`
+}
+
+type TextAnalysis struct {
+	Text
+	TokenCount    int         `json:"token_count"`
+	Tokens        []TextToken `json:"tokens"`
+	UnknownTokens []string    `json:"unknown_tokens"`
+}
+
+// A token glossed with the first definition of its word. Tokens that match no
+// word have no WordID and a gloss of "?".
+type GlossedToken struct {
+	Token        string     `json:"token"`
+	WordID       *uuid.UUID `json:"word_id,omitempty"`
+	Gloss        string     `json:"gloss"`
+	PartOfSpeech string     `json:"part_of_speech,omitempty"`
+}
+
+type GlossLine struct {
+	Line   int            `json:"line"`
+	Text   string         `json:"text"`
+	Tokens []GlossedToken `json:"tokens"`
+}
+
+// Coverage is the share of tokens that matched a word.
+type TextGloss struct {
+	TextID        uuid.UUID   `json:"text_id"`
+	Title         string      `json:"title"`
+	Lines         []GlossLine `json:"lines"`
+	UnknownTokens []string    `json:"unknown_tokens"`
+	Coverage      float64     `json:"coverage"`
+}
+
+type WordFrequency struct {
+	WordID uuid.UUID `json:"word_id"`
+	Word   string    `json:"word"`
+	Count  int       `json:"count"`
+	Rank   int       `json:"rank"`
+}
+
+type ConcordanceLine struct {
+	TextID uuid.UUID `json:"text_id"`
+	Title  string    `json:"title"`
+	corpus.Occurrence
+}
+
+type Concordance struct {
+	WordID      uuid.UUID         `json:"word_id"`
+	Word        string            `json:"word"`
+	Context     int               `json:"context"`
+	Occurrences []ConcordanceLine `json:"occurrences"`
+}
+
+// What an import of a dictionary file wrote to a language.
+type LexiconImport struct {
+	Language           Language `json:"language"`
+	WordsCreated       int      `json:"words_created"`
+	WordsExisting      int      `json:"words_existing"`
+	DefinitionsCreated int      `json:"definitions_created"`
+	ExamplesCreated    int      `json:"examples_created"`
+	unglossed          int
+}
+
+type LiftImport struct {
+	LexiconImport
+	Unmapped []lift.UnmappedField `json:"unmapped"`
+	Warnings []string             `json:"warnings"`
+}
+
+type SFMImport struct {
+	LexiconImport
+	Failed   []sfm.Failure        `json:"failed_records"`
+	Unmapped []sfm.UnmappedMarker `json:"unmapped"`
+	Warnings []string             `json:"warnings"`
+}
+
+// A row of an imported spreadsheet that was skipped or failed, and why.
+type RowReport struct {
+	Line   int    `json:"line"`
+	Word   string `json:"word"`
+	Reason string `json:"reason"`
+}
+
+type SpreadsheetImport struct {
+	DryRun             bool        `json:"dry_run"`
+	Mode               string      `json:"mode"`
+	Language           Language    `json:"language"`
+	Rows               int         `json:"rows"`
+	WordsCreated       int         `json:"words_created"`
+	WordsUpdated       int         `json:"words_updated"`
+	DefinitionsCreated int         `json:"definitions_created"`
+	DefinitionsUpdated int         `json:"definitions_updated"`
+	IgnoredColumns     []string    `json:"ignored_columns"`
+	Skipped            []RowReport `json:"skipped"`
+	Errors             []RowReport `json:"errors"`
+}
+
+// A word as written to an archive. Unlike Word, it keeps the difference
+// between an empty and a missing font-formatted form.
+type ArchivedWord struct {
+	ID            uuid.UUID `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Word          string    `json:"word"`
+	FontFormatted *string   `json:"font_formatted"`
+	LanguageID    uuid.UUID `json:"language_id"`
+}
+
+func getArchivedWord(w database.Word) ArchivedWord {
+	archived := ArchivedWord{
+		ID:         w.ID,
+		CreatedAt:  w.CreatedAt,
+		UpdatedAt:  w.UpdatedAt,
+		Word:       w.Word,
+		LanguageID: w.LanguageID,
+	}
+
+	if w.FontFormatted.Valid {
+		archived.FontFormatted = &w.FontFormatted.String
+	}
+
+	return archived
+}
+
+type Archive struct {
+	Format      string         `json:"format"`
+	Version     int            `json:"version"`
+	ExportedAt  time.Time      `json:"exported_at"`
+	Languages   []Language     `json:"languages"`
+	Words       []ArchivedWord `json:"words"`
+	Definitions []Definition   `json:"definitions"`
+	Complete    bool           `json:"complete"`
+}
+
+// Restored rows were inserted or overwrote an older copy. Unchanged rows
+// already existed with a copy at least as recent. Skipped rows were left out
+// by a merge.
+type RestoreCounts struct {
+	Restored  int `json:"restored"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"`
+}
+
+// A row of an archive that a merge left out, and why. Name is the language's
+// name, the word, or the definition's content.
+type SkippedRow struct {
+	Kind   string    `json:"kind"`
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Reason string    `json:"reason"`
+}
+
+type ArchiveImport struct {
+	Strategy    string        `json:"strategy"`
+	Version     int           `json:"version"`
+	Languages   RestoreCounts `json:"languages"`
+	Words       RestoreCounts `json:"words"`
+	Definitions RestoreCounts `json:"definitions"`
+	Skipped     []SkippedRow  `json:"skipped"`
+}